@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"my-id-key/backend/engine"
@@ -21,8 +22,11 @@ type App struct {
 
 	basePath string
 
-	keysDir    string
-	reportsDir string
+	keysDir     string
+	reportsDir  string
+	verifiedDir string // Confirmed identifications, one JSON file per matrix
+	verifiedMu  sync.Mutex
+	verified    *verifiedCache // Stored cases of the current matrix and their error counts
 	// ★ ヘルパー画像のディレクトリパスを追加
 	helperImagesDir string
 	currentKey      string
@@ -55,6 +59,9 @@ func (a *App) startup(ctx context.Context) {
 	runtime.LogInfof(a.ctx, "Reports directory set to: %s", a.reportsDir)
 	_ = os.MkdirAll(a.reportsDir, 0o755)
 
+	a.verifiedDir = filepath.Join(a.basePath, "verified_cases")
+	runtime.LogInfof(a.ctx, "Verified cases directory set to: %s", a.verifiedDir)
+
 	// ★ ヘルパー画像ディレクトリを設定
 	a.helperImagesDir = filepath.Join(a.basePath, "helper_materi")
 	runtime.LogInfof(a.ctx, "Helper images directory set to: %s", a.helperImagesDir)
@@ -112,6 +119,10 @@ func (a *App) setCurrentKeyByPath(p string) error {
 		return fmt.Errorf("load matrix: %w", err)
 	}
 
+	for _, issue := range matrix.Issues {
+		runtime.LogWarning(a.ctx, issue.Error())
+	}
+
 	a.currentMatrix = matrix
 	a.currentPath = p
 	a.currentKey = filepath.Base(p)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -111,6 +112,21 @@ func (a *App) GetMatrix() (*engine.Matrix, error) {
 	return a.currentMatrix, nil
 }
 
+// GetMatrixIssues returns the non-fatal problems found while loading the current
+// matrix, such as #Dependency conditions that could not be parsed or resolved.
+func (a *App) GetMatrixIssues() []engine.LoadIssue {
+	if a.currentMatrix == nil {
+		return nil
+	}
+	return a.currentMatrix.Issues
+}
+
+// ListAlgorithms returns the registered identification algorithms with the
+// AlgoOptions fields each one reads, so the UI can build its settings from them.
+func (a *App) ListAlgorithms() []engine.AlgorithmInfo {
+	return engine.Algorithms()
+}
+
 // GetTaxonDetails returns all available data for a single taxon.
 func (a *App) GetTaxonDetails(taxonID string) (*engine.Taxon, error) {
 	if a.currentMatrix == nil {
@@ -147,6 +163,8 @@ func (a *App) ApplyFiltersAlgoOpt(req ApplyRequest) (*ApplyResultEx, error) {
 	log.Printf("[API] Received Mode: %s, Algo: %s", req.Mode, req.Algo)
 	log.Printf("[API] Received Selected (Binary/Continuous): %+v", req.Selected)
 	log.Printf("[API] Received SelectedMulti (Categorical): %+v", req.SelectedMulti)
+	log.Printf("[API] Received SelectedNA (Unobservable): %+v, groups: %v", req.SelectedNA, req.SelectedNAGroups)
+	log.Printf("[API] Received SelectedNominal: %+v, SelectedContinuous: %+v", req.SelectedNominal, req.SelectedContinuous)
 
 	if a.currentMatrix == nil {
 		if _, err := a.GetMatrix(); err != nil {
@@ -154,30 +172,12 @@ func (a *App) ApplyFiltersAlgoOpt(req ApplyRequest) (*ApplyResultEx, error) {
 		}
 	}
 
-	eopts := engine.AlgoOptions{
-		DefaultAlphaFP:         req.Opts.DefaultAlphaFP,
-		DefaultBetaFN:          req.Opts.DefaultBetaFN,
-		GammaNAPenalty:         req.Opts.GammaNAPenalty,
-		WantInfoGain:           req.Opts.WantInfoGain,
-		UsePragmaticScore:      req.Opts.UsePragmaticScore,
-		RecommendationStrategy: req.Opts.RecommendationStrategy,
-		Lambda:                 req.Opts.Lambda,
-		A0:                     req.Opts.A0,
-		B0:                     req.Opts.B0,
-		Kappa:                  req.Opts.Kappa,
-		ConflictPenalty:        req.Opts.ConflictPenalty,
-		ToleranceFactor:        req.Opts.ToleranceFactor,
-		CategoricalAlgo:        req.Opts.CategoricalAlgo,
-		JaccardThreshold:       req.Opts.JaccardThreshold,
-	}
-
 	res, err := engine.ApplyFiltersAlgoOpt(
 		a.currentMatrix,
-		req.Selected,
-		req.SelectedMulti,
+		req.observations(),
 		req.Mode,
 		req.Algo,
-		eopts,
+		a.engineOptions(req),
 	)
 	if err != nil {
 		log.Printf("Error from engine.ApplyFiltersAlgoOpt: %v", err)
@@ -188,9 +188,31 @@ func (a *App) ApplyFiltersAlgoOpt(req ApplyRequest) (*ApplyResultEx, error) {
 	return &ApplyResultEx{
 		Scores:      res.Scores,
 		Suggestions: res.Suggestions,
+		Inactive:    res.Inactive,
+		Dropped:     res.Dropped,
+		Unknown:     res.Unknown,
+		Hierarchy:   res.Hierarchy,
+		Credible:    res.Credible,
+		Verdict:     res.Verdict,
 	}, nil
 }
 
+// ApplyEnsemble runs every registered algorithm on the request's observations
+// and returns the rankings side by side with a consensus rank. req.Algo is ignored.
+func (a *App) ApplyEnsemble(req ApplyRequest) (*engine.EnsembleResult, error) {
+	if a.currentMatrix == nil {
+		if _, err := a.GetMatrix(); err != nil {
+			return nil, err
+		}
+	}
+	res, err := engine.ApplyEnsemble(a.currentMatrix, req.observations(), req.Mode, a.engineOptions(req))
+	if err != nil {
+		log.Printf("Error from engine.ApplyEnsemble: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
 // SaveReport はフロントエンドから受け取ったHTMLコンテンツを指定された形式で保存します。
 func (a *App) SaveReport(htmlContent string, format string, defaultName string) (string, error) {
 	var dialogOptions runtime.SaveDialogOptions
@@ -298,7 +320,14 @@ func saveAsDocx(htmlContent string, path string) error {
 }
 
 // GetJustificationForTaxon returns a breakdown of which traits match, conflict, or are unobserved for a given taxon.
-func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int, selectedMulti map[string][]string) (*Justification, error) {
+// Continuous measurements come in selectedContinuous; whole numbers in selected are still accepted.
+func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int, selectedMulti map[string][]string, selectedContinuous map[string]engine.ContinuousObservation) (*Justification, error) {
+	return a.GetJustificationForRequest(taxonID, ApplyRequest{Selected: selected, SelectedMulti: selectedMulti, SelectedContinuous: selectedContinuous})
+}
+
+// GetJustificationForRequest is GetJustificationForTaxon driven by the full ApplyRequest,
+// so that unobservable marks (SelectedNA/SelectedNAGroups) are reflected as well.
+func (a *App) GetJustificationForRequest(taxonID string, req ApplyRequest) (*Justification, error) {
 	if a.currentMatrix == nil {
 		return nil, fmt.Errorf("no matrix loaded")
 	}
@@ -331,6 +360,9 @@ func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int,
 
 	justification := &Justification{}
 
+	obs, unobservable, inactive := req.observations().Resolve(a.currentMatrix, req.Opts.ApplyDependencies)
+	selected, selectedMulti := obs.Selected, obs.SelectedMulti
+
 	allSelections := make(map[string]bool)
 	for k := range selected {
 		allSelections[k] = true
@@ -338,12 +370,40 @@ func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int,
 	for k := range selectedMulti {
 		allSelections[k] = true
 	}
+	for k := range obs.SelectedNominal {
+		allSelections[k] = true
+	}
+	for k := range obs.SelectedContinuous {
+		allSelections[k] = true
+	}
 
 	for _, trait := range a.currentMatrix.Traits {
 		if trait.Type == "derived" {
 			continue
 		}
 
+		if unobservable[trait.ID] {
+			justification.Unobservable = append(justification.Unobservable, JustificationItem{
+				TraitName:      trait.NameEN,
+				TraitGroupName: trait.GroupEN,
+				UserChoice:     "Unobservable",
+				Status:         "unobservable",
+			})
+			continue
+		}
+
+		if inactive[trait.ID] {
+			dep := trait.ParentDependency
+			justification.Inapplicable = append(justification.Inapplicable, JustificationItem{
+				TraitName:      trait.NameEN,
+				TraitGroupName: trait.GroupEN,
+				UserChoice:     "Inapplicable",
+				TaxonState:     "requires " + dep.Expr,
+				Status:         "inapplicable",
+			})
+			continue
+		}
+
 		userChoiceStr := "Unobserved"
 		taxonStateStr := "NA"
 		status := "unobserved"
@@ -376,7 +436,7 @@ func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int,
 			taxonState, _ := targetTaxon.Traits[trait.ID]
 			taxonStateStr = ternaryToString(taxonState)
 			if userChoice != 0 && taxonState != 0 {
-				if userChoice == int(taxonState) {
+				if userChoice == int(taxonState) || taxonState == engine.Variable {
 					status = "match"
 				} else {
 					status = "conflict"
@@ -386,12 +446,15 @@ func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int,
 			}
 
 		case "continuous":
-			userValue, _ := selected[trait.ID]
-			userChoiceStr = fmt.Sprintf("%v", userValue)
+			userValue := obs.SelectedContinuous[trait.ID]
+			userChoiceStr = formatMeasurement(userValue)
 			taxonValue, ok := targetTaxon.ContinuousTraits[trait.ID]
 			if ok {
 				taxonStateStr = fmt.Sprintf("[%.2f, %.2f]", taxonValue.Min, taxonValue.Max)
-				if float64(userValue) >= taxonValue.Min && float64(userValue) <= taxonValue.Max {
+				if taxonValue.SD > 0 {
+					taxonStateStr = fmt.Sprintf("%.2f ± %.2f", taxonValue.Mean, taxonValue.SD)
+				}
+				if userValue.Value+math.Abs(userValue.Error) >= taxonValue.Min && userValue.Value-math.Abs(userValue.Error) <= taxonValue.Max {
 					status = "match"
 				} else {
 					status = "conflict"
@@ -419,26 +482,45 @@ func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int,
 
 		case "nominal_parent":
 			var chosenChild engine.Trait
-			for _, child := range childrenMap[trait.TraitID] {
-				if val, ok := selected[child.ID]; ok && val == 1 {
+			chosenIdx := -1
+			label, byLabel := obs.SelectedNominal[trait.ID]
+			for i, child := range childrenMap[trait.TraitID] {
+				if byLabel && strings.EqualFold(child.State, label) {
 					chosenChild = child
+					chosenIdx = i
+					break
+				}
+				if val, ok := selected[child.ID]; !byLabel && ok && val == 1 {
+					chosenChild = child
+					chosenIdx = i
 					break
 				}
 			}
 			userChoiceStr = chosenChild.State
 
-			var taxonChildState engine.Trait
-			for _, child := range childrenMap[trait.TraitID] {
+			// A taxon may be recorded with several states; the answer matches any of them.
+			var taxonStates []string
+			minDist := -1
+			for i, child := range childrenMap[trait.TraitID] {
 				if val, ok := targetTaxon.Traits[child.ID]; ok && val == 1 {
-					taxonChildState = child
-					break
+					taxonStates = append(taxonStates, child.State)
+					d := i - chosenIdx
+					if d < 0 {
+						d = -d
+					}
+					if chosenIdx >= 0 && (minDist < 0 || d < minDist) {
+						minDist = d
+					}
 				}
 			}
 
-			if taxonChildState.ID != "" {
-				taxonStateStr = taxonChildState.State
-				if chosenChild.ID == taxonChildState.ID {
+			if len(taxonStates) > 0 {
+				taxonStateStr = strings.Join(taxonStates, "; ")
+				if minDist == 0 {
 					status = "match"
+				} else if trait.Ordinal && minDist == 1 {
+					// Adjacent ordinal states are a near miss, mirroring the engine's likelihood.
+					status = "near"
 				} else {
 					status = "conflict"
 				}
@@ -448,6 +530,11 @@ func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int,
 			}
 		}
 
+		if targetTaxon.Inapplicable[trait.ID] {
+			taxonStateStr = "Inapplicable"
+			status = "conflict"
+		}
+
 		item := JustificationItem{
 			TraitName:      trait.NameEN,
 			TraitGroupName: trait.GroupEN,
@@ -456,7 +543,7 @@ func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int,
 			Status:         status,
 		}
 
-		if status == "match" {
+		if status == "match" || status == "near" {
 			justification.Matches = append(justification.Matches, item)
 		} else if status == "conflict" {
 			justification.Conflicts = append(justification.Conflicts, item)
@@ -471,6 +558,61 @@ func (a *App) GetJustificationForTaxon(taxonID string, selected map[string]int,
 
 // --- Helper Functions ---
 
+// observations converts the request into the engine's observation bundle.
+func (req ApplyRequest) observations() engine.Observations {
+	return engine.Observations{
+		Selected:           req.Selected,
+		SelectedMulti:      req.SelectedMulti,
+		SelectedNA:         req.SelectedNA,
+		SelectedNAGroups:   req.SelectedNAGroups,
+		SelectedNominal:    req.SelectedNominal,
+		SelectedContinuous: req.SelectedContinuous,
+	}
+}
+
+// algoOptions converts the request options into the engine's AlgoOptions.
+func (req ApplyRequest) algoOptions() engine.AlgoOptions {
+	return engine.AlgoOptions{
+		DefaultAlphaFP:         req.Opts.DefaultAlphaFP,
+		DefaultBetaFN:          req.Opts.DefaultBetaFN,
+		GammaNAPenalty:         req.Opts.GammaNAPenalty,
+		WantInfoGain:           req.Opts.WantInfoGain,
+		UsePragmaticScore:      req.Opts.UsePragmaticScore,
+		RecommendationStrategy: req.Opts.RecommendationStrategy,
+		ApplyDependencies:      req.Opts.ApplyDependencies,
+		PlanDepth:              req.Opts.PlanDepth,
+		Lambda:                 req.Opts.Lambda,
+		PriorStrength:          req.Opts.PriorStrength,
+		Kappa:                  req.Opts.Kappa,
+		ConflictPenalty:        req.Opts.ConflictPenalty,
+		ToleranceFactor:        req.Opts.ToleranceFactor,
+		CategoricalAlgo:        req.Opts.CategoricalAlgo,
+		ContinuousAlgo:         req.Opts.ContinuousAlgo,
+		RangeCoverage:          req.Opts.RangeCoverage,
+		MaxConflicts:           req.Opts.MaxConflicts,
+		DisagreementRanks:      req.Opts.DisagreementRanks,
+		UnknownPrior:           req.Opts.UnknownPrior,
+		RankThreshold:          req.Opts.RankThreshold,
+		CredibleLevel:          req.Opts.CredibleLevel,
+		StopRatio:              req.Opts.StopRatio,
+		MinInfoGain:            req.Opts.MinInfoGain,
+		JaccardThreshold:       req.Opts.JaccardThreshold,
+		AlphaFP:                req.Opts.AlphaFP,
+		BetaFN:                 req.Opts.BetaFN,
+		Priors:                 req.Opts.Priors,
+		Confidence:             req.Opts.Confidence,
+		Confirmed:              req.Opts.Confirmed,
+	}
+}
+
+// formatMeasurement renders a continuous observation as "12.4" or "12.4 ± 0.2".
+func formatMeasurement(v engine.ContinuousObservation) string {
+	if v.Error != 0 {
+		return fmt.Sprintf("%g ± %g", v.Value, math.Abs(v.Error))
+	}
+	return fmt.Sprintf("%g", v.Value)
+}
+
 func ternaryToString(t engine.Ternary) string {
 	switch t {
 	case engine.Yes:
@@ -479,6 +621,8 @@ func ternaryToString(t engine.Ternary) string {
 		return "No"
 	case engine.NA:
 		return "NA"
+	case engine.Variable:
+		return "Variable"
 	default:
 		return "Unknown"
 	}
@@ -0,0 +1,188 @@
+// app_verified.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"my-id-key/backend/engine"
+)
+
+// verifiedCasesPath is the JSON file holding the confirmed cases of the current matrix.
+func (a *App) verifiedCasesPath() (string, error) {
+	if a.currentKey == "" {
+		return "", errors.New("no matrix loaded")
+	}
+	dir := a.verifiedDir
+	if dir == "" {
+		dir = filepath.Join(a.basePath, "verified_cases")
+	}
+	name := strings.TrimSuffix(a.currentKey, filepath.Ext(a.currentKey))
+	return filepath.Join(dir, name+".json"), nil
+}
+
+func (a *App) loadVerifiedCases() ([]VerifiedCase, error) {
+	p, err := a.verifiedCasesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cases []VerifiedCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("read %s: %w", filepath.Base(p), err)
+	}
+	return cases, nil
+}
+
+func (a *App) saveVerifiedCases(cases []VerifiedCase) error {
+	p, err := a.verifiedCasesPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cases, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = writeText(p, string(data))
+	a.verifiedMu.Lock()
+	a.verified = nil
+	a.verifiedMu.Unlock()
+	return err
+}
+
+// verifiedCache holds the stored cases of one matrix with their error counts,
+// so that evaluations neither re-read the file nor re-resolve every case.
+type verifiedCache struct {
+	matrix *engine.Matrix
+	path   string
+	cases  []VerifiedCase
+	counts map[string]engine.TraitErrorCounts
+}
+
+// cachedVerifiedCases returns the stored cases of the current matrix, reading
+// and counting them again only after the matrix changed or a case was added
+// or deleted. The result is shared and must not be modified.
+func (a *App) cachedVerifiedCases() (*verifiedCache, error) {
+	p, err := a.verifiedCasesPath()
+	if err != nil {
+		return nil, err
+	}
+	a.verifiedMu.Lock()
+	defer a.verifiedMu.Unlock()
+	if c := a.verified; c != nil && c.matrix == a.currentMatrix && c.path == p {
+		return c, nil
+	}
+	cases, err := a.loadVerifiedCases()
+	if err != nil {
+		return nil, err
+	}
+	c := &verifiedCache{
+		matrix: a.currentMatrix,
+		path:   p,
+		cases:  cases,
+		counts: engine.CountTraitErrors(a.currentMatrix, confirmedIDs(cases)),
+	}
+	a.verified = c
+	return c, nil
+}
+
+// AddVerifiedCase stores the observations of req as a confirmed identification
+// of taxonID, e.g. after dissection. Stored cases calibrate per-trait error rates.
+func (a *App) AddVerifiedCase(taxonID string, req ApplyRequest, note string) error {
+	m, err := a.GetMatrix()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, t := range m.Taxa {
+		if t.ID == taxonID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("taxon %q not found", taxonID)
+	}
+	cases, err := a.loadVerifiedCases()
+	if err != nil {
+		return err
+	}
+	cases = append(cases, VerifiedCase{
+		ConfirmedID: engine.ConfirmedID{TaxonID: taxonID, Observations: req.observations()},
+		RecordedAt:  time.Now().Format(time.RFC3339),
+		Note:        note,
+	})
+	return a.saveVerifiedCases(cases)
+}
+
+// ListVerifiedCases returns the confirmed identifications stored for the current matrix.
+func (a *App) ListVerifiedCases() ([]VerifiedCase, error) {
+	c, err := a.cachedVerifiedCases()
+	if err != nil {
+		return nil, err
+	}
+	return c.cases, nil
+}
+
+// DeleteVerifiedCase removes the stored case at index (as returned by ListVerifiedCases).
+func (a *App) DeleteVerifiedCase(index int) error {
+	cases, err := a.loadVerifiedCases()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(cases) {
+		return fmt.Errorf("no verified case at index %d", index)
+	}
+	return a.saveVerifiedCases(append(cases[:index], cases[index+1:]...))
+}
+
+// SuggestErrorRateUpdates estimates #AlphaFP, #BetaFN and #Risk for every trait
+// the stored cases cover, as posterior means under a prior worth strength answers
+// (<= 0 means 2) centred on the current values; defaultAlpha/defaultBeta
+// are the global rates used for traits without their own.
+func (a *App) SuggestErrorRateUpdates(strength, defaultAlpha, defaultBeta float64) ([]engine.TraitCalibration, error) {
+	m, err := a.GetMatrix()
+	if err != nil {
+		return nil, err
+	}
+	c, err := a.cachedVerifiedCases()
+	if err != nil {
+		return nil, err
+	}
+	return engine.CalibrateTraitCounts(m, c.counts, strength, defaultAlpha, defaultBeta), nil
+}
+
+// engineOptions builds the engine options for req, adding the error counts of
+// the stored cases when the request asks for them.
+func (a *App) engineOptions(req ApplyRequest) engine.AlgoOptions {
+	opts := req.algoOptions()
+	if req.Opts.UseVerifiedCases {
+		c, err := a.cachedVerifiedCases()
+		if err != nil {
+			log.Printf("Could not read verified cases: %v", err)
+			return opts
+		}
+		opts.ErrorCounts = c.counts
+	}
+	return opts
+}
+
+func confirmedIDs(cases []VerifiedCase) []engine.ConfirmedID {
+	out := make([]engine.ConfirmedID, len(cases))
+	for i, c := range cases {
+		out[i] = c.ConfirmedID
+	}
+	return out
+}
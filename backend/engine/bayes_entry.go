@@ -45,6 +45,11 @@ func EvaluateBayesA2FromOptions(
 		ToleranceFactor:  opt.ToleranceFactor,
 		CategoricalAlgo:  opt.CategoricalAlgo,
 		JaccardThreshold: opt.JaccardThreshold,
+		ContinuousAlgo:   opt.ContinuousAlgo,
+		RangeCoverage:    opt.RangeCoverage,
+		Lambda:           opt.Lambda,
+		TraitAlphaFP:     opt.AlphaFP,
+		TraitBetaFN:      opt.BetaFN,
 	}
 
 	// ---- Bayes 本体の評価 ----
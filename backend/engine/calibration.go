@@ -0,0 +1,104 @@
+// backend/engine/calibration.go
+package engine
+
+import (
+	"math"
+	"sort"
+)
+
+// TraitCalibration compares a trait's matrix error settings with what the
+// confirmed identifications suggest. Suggested values are posterior means under
+// a prior of PriorStrength answers centred on the current setting; a zero Suggested
+// value means the cases did not cover that direction.
+type TraitCalibration struct {
+	ID      string           `json:"id"`                // Internal trait ID
+	TraitID string           `json:"traitId,omitempty"` // #TraitID to edit in the matrix
+	NameEN  string           `json:"name_en"`
+	NameJP  string           `json:"name_jp"`
+	Counts  TraitErrorCounts `json:"counts"`
+
+	AlphaFP          float64 `json:"alphaFP"` // Current #AlphaFP (0 = global default)
+	SuggestedAlphaFP float64 `json:"suggestedAlphaFP,omitempty"`
+	BetaFN           float64 `json:"betaFN"` // Current #BetaFN (0 = global default)
+	SuggestedBetaFN  float64 `json:"suggestedBetaFN,omitempty"`
+	Risk             float64 `json:"risk"`
+	SuggestedRisk    float64 `json:"suggestedRisk"`
+	RiskLabel        string  `json:"riskLabel"` // Nearest #Risk keyword for SuggestedRisk
+}
+
+// riskLevels are the #Risk keywords understood by parseRisk.
+var riskLevels = []struct {
+	label string
+	value float64
+}{
+	{"lowest", 0.0}, {"low", 0.2}, {"medium", 0.5}, {"high", 0.8}, {"highest", 1.0},
+}
+
+// CalibrateTraits estimates per-trait error rates from confirmed identifications
+// and returns one entry per trait the cases cover, ordered by how far the
+// suggested #Risk is from the current one.
+//
+// The suggested #Risk is the pooled misreading rate relative to chance: a trait
+// read wrongly half the time (a coin toss for a binary trait) is "highest", one
+// read wrongly one time in ten is "low". Traits without #AlphaFP/#BetaFN are
+// centred on defaultAlpha/defaultBeta, and traits without #Risk on the mean of
+// their two error rates.
+func CalibrateTraits(m *Matrix, confirmed []ConfirmedID, strength, defaultAlpha, defaultBeta float64) []TraitCalibration {
+	return CalibrateTraitCounts(m, CountTraitErrors(m, confirmed), strength, defaultAlpha, defaultBeta)
+}
+
+// CalibrateTraitCounts is CalibrateTraits for answers already tallied by CountTraitErrors.
+func CalibrateTraitCounts(m *Matrix, counts map[string]TraitErrorCounts, strength, defaultAlpha, defaultBeta float64) []TraitCalibration {
+	if m == nil || len(counts) == 0 {
+		return nil
+	}
+
+	var out []TraitCalibration
+	for _, t := range m.Traits {
+		c, ok := counts[t.ID]
+		if !ok {
+			continue
+		}
+		cal := TraitCalibration{
+			ID:      t.ID,
+			TraitID: t.TraitID,
+			NameEN:  t.NameEN,
+			NameJP:  t.NameJP,
+			Counts:  c,
+			AlphaFP: t.AlphaFP,
+			BetaFN:  t.BetaFN,
+			Risk:    t.Risk,
+		}
+		alpha := priorRate(t.AlphaFP, defaultAlpha, fallbackAlphaFP)
+		beta := priorRate(t.BetaFN, defaultBeta, fallbackBetaFN)
+		if v, ok := c.posteriorAlpha(alpha, strength); ok {
+			cal.SuggestedAlphaFP = v
+		}
+		if v, ok := c.posteriorBeta(beta, strength); ok {
+			cal.SuggestedBetaFN = v
+		}
+		// The pooled rate is centred on the current #Risk, read back as a misreading rate.
+		pa, pb := betaPrior(priorRate(0.5*t.Risk, 0, (alpha+beta)/2), strength)
+		misreads := float64(c.FalsePositives + c.FalseNegatives)
+		trials := float64(c.Negatives + c.Positives)
+		pooled := (pa + misreads) / (pa + pb + trials)
+		cal.SuggestedRisk = math.Min(1, pooled/0.5)
+		cal.RiskLabel = nearestRiskLabel(cal.SuggestedRisk)
+		out = append(out, cal)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return math.Abs(out[i].SuggestedRisk-out[i].Risk) > math.Abs(out[j].SuggestedRisk-out[j].Risk)
+	})
+	return out
+}
+
+func nearestRiskLabel(v float64) string {
+	best, bestDist := riskLevels[0].label, math.Inf(1)
+	for _, l := range riskLevels {
+		if d := math.Abs(v - l.value); d < bestDist {
+			best, bestDist = l.label, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,91 @@
+// backend/engine/calibration_test.go
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+// calibrationCases confirms A five times and B five times. t1 is answered "no"
+// on three of the A cases; t2 is always read correctly.
+func calibrationCases() (*Matrix, []ConfirmedID) {
+	m := &Matrix{
+		Traits: []Trait{
+			{ID: "t1", TraitID: "hairs", NameEN: "Hairs", Type: "binary"},
+			{ID: "t2", TraitID: "wings", NameEN: "Wings", Type: "binary"},
+		},
+		Taxa: []Taxon{
+			{ID: "A", Name: "A", Traits: map[string]Ternary{"t1": Yes, "t2": Yes}},
+			{ID: "B", Name: "B", Traits: map[string]Ternary{"t1": No, "t2": No}},
+		},
+	}
+	var cases []ConfirmedID
+	for i := 0; i < 5; i++ {
+		hairs := 1
+		if i < 3 {
+			hairs = -1
+		}
+		cases = append(cases,
+			ConfirmedID{TaxonID: "A", Observations: Observations{Selected: map[string]int{"t1": hairs, "t2": 1}}},
+			ConfirmedID{TaxonID: "B", Observations: Observations{Selected: map[string]int{"t1": -1, "t2": -1}}},
+		)
+	}
+	return m, cases
+}
+
+// Suggested rates are posterior means: (strength*prior + errors) / (strength + trials).
+func TestCalibrateTraits(t *testing.T) {
+	m, cases := calibrationCases()
+	got := CalibrateTraits(m, cases, 2, 0.03, 0.07)
+	if len(got) != 2 {
+		t.Fatalf("%d calibrations, want 2", len(got))
+	}
+
+	t1 := got[0]
+	if t1.ID != "t1" {
+		t.Fatalf("first calibration is %s, want the misread t1", t1.ID)
+	}
+	want := TraitErrorCounts{Negatives: 5, Positives: 5, FalseNegatives: 3}
+	if t1.Counts != want {
+		t.Errorf("t1 counts %+v, want %+v", t1.Counts, want)
+	}
+	if math.Abs(t1.SuggestedBetaFN-(2*0.07+3)/7) > 1e-9 {
+		t.Errorf("t1 suggested BetaFN %.4f, want %.4f", t1.SuggestedBetaFN, (2*0.07+3)/7)
+	}
+	if math.Abs(t1.SuggestedAlphaFP-2*0.03/7) > 1e-9 {
+		t.Errorf("t1 suggested AlphaFP %.4f, want %.4f", t1.SuggestedAlphaFP, 2*0.03/7)
+	}
+	if t1.RiskLabel != "medium" || got[1].RiskLabel != "lowest" {
+		t.Errorf("risk labels t1 %q, t2 %q; want medium, lowest", t1.RiskLabel, got[1].RiskLabel)
+	}
+}
+
+// Tallied counts (the app's verified-case cache) calibrate like the cases they
+// came from, and merge with confirmed cases sent along with a request.
+func TestErrorCountsMatchConfirmed(t *testing.T) {
+	m, cases := calibrationCases()
+	counts := CountTraitErrors(m, cases)
+
+	fromCases := CalibrateTraits(m, cases, 2, 0.03, 0.07)
+	fromCounts := CalibrateTraitCounts(m, counts, 2, 0.03, 0.07)
+	for i := range fromCases {
+		if fromCases[i] != fromCounts[i] {
+			t.Errorf("%s: cases give %+v, counts give %+v", fromCases[i].ID, fromCases[i], fromCounts[i])
+		}
+	}
+
+	opt := AlgoOptions{DefaultAlphaFP: 0.03, DefaultBetaFN: 0.07, PriorStrength: 2}
+	opt.Confirmed = cases
+	_, betaCases := resolveTraitErrorRates(m, opt)
+	opt.Confirmed, opt.ErrorCounts = nil, counts
+	_, betaCounts := resolveTraitErrorRates(m, opt)
+	if betaCases["t1"] != betaCounts["t1"] {
+		t.Errorf("t1 BetaFN from cases %.4f, from counts %.4f", betaCases["t1"], betaCounts["t1"])
+	}
+
+	opt.Confirmed = cases
+	_, betaBoth := resolveTraitErrorRates(m, opt)
+	if want := (2*0.07 + 6) / 12; math.Abs(betaBoth["t1"]-want) > 1e-9 {
+		t.Errorf("t1 BetaFN from cases and counts %.4f, want %.4f", betaBoth["t1"], want)
+	}
+}
@@ -0,0 +1,312 @@
+// backend/engine/dependency.go
+package engine
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// condition returns the parsed #Dependency condition. Dependencies built without
+// the loader fall back to the single "parent = state" rule.
+func (d *Dependency) condition() depExpr {
+	if d.cond != nil {
+		return d.cond
+	}
+	return &depCompare{ParentTraitID: d.ParentTraitID, Op: "=", Value: d.RequiredState}
+}
+
+// parseDependency parses a #Dependency cell and resolves every referenced trait
+// to its canonical #TraitID. A single "parent = state" condition also fills
+// ParentTraitID/RequiredState, which the trait list uses to hide rows.
+func parseDependency(s string, resolver map[string]string) (*Dependency, error) {
+	cond, err := parseDependencyExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cond.comparisons() {
+		resolved, ok := resolver[c.ParentTraitID]
+		if !ok {
+			resolved, ok = resolver[sanitizeToID(c.ParentTraitID)]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown parent trait %q", c.ParentTraitID)
+		}
+		c.ParentTraitID = resolved
+	}
+	dep := &Dependency{Expr: s, cond: cond}
+	if c, ok := cond.(*depCompare); ok && c.Op == "=" {
+		dep.ParentTraitID = c.ParentTraitID
+		dep.RequiredState = c.Value
+	}
+	return dep, nil
+}
+
+// validateDependencies checks every dependency against the loaded traits: ordered
+// comparisons need a continuous parent with a numeric value or an ordinal parent
+// with a known state, and dependencies may not form cycles. Offending dependencies
+// are dropped and reported as load issues; rows maps #TraitID to its sheet row.
+func validateDependencies(m *Matrix, rows map[string]int) {
+	byTraitID := make(map[string]Trait, len(m.Traits))
+	for _, t := range m.Traits {
+		if t.Type != "derived" {
+			byTraitID[t.TraitID] = t
+		}
+	}
+	drop := func(i int, msg string) {
+		t := m.Traits[i]
+		m.Issues = append(m.Issues, LoadIssue{
+			Sheet:   "Traits",
+			Row:     rows[t.TraitID],
+			TraitID: t.TraitID,
+			Column:  "#Dependency",
+			Value:   t.ParentDependency.Expr,
+			Message: msg,
+		})
+		m.Traits[i].ParentDependency = nil
+	}
+
+	for i, t := range m.Traits {
+		if t.ParentDependency == nil {
+			continue
+		}
+		for _, c := range t.ParentDependency.condition().comparisons() {
+			parent, ok := byTraitID[c.ParentTraitID]
+			if !ok {
+				drop(i, fmt.Sprintf("unknown parent trait %q", c.ParentTraitID))
+				break
+			}
+			if c.Op == "=" || c.Op == "!=" {
+				continue
+			}
+			_, numErr := strconv.ParseFloat(c.Value, 64)
+			if parent.Type == "continuous" && numErr == nil {
+				continue
+			}
+			if parent.Type == "nominal_parent" && parent.Ordinal && stateIndexOf(parent.States, c.Value) >= 0 {
+				continue
+			}
+			drop(i, fmt.Sprintf("%q %s %q needs a continuous parent with a number or an ordinal parent with one of its states", c.ParentTraitID, c.Op, c.Value))
+			break
+		}
+	}
+
+	// Depth-first search over trait -> parent edges; a grey node reached again closes a cycle.
+	const (
+		white = iota
+		grey
+		black
+	)
+	index := make(map[string]int, len(m.Traits))
+	for i, t := range m.Traits {
+		if t.Type != "derived" {
+			index[t.TraitID] = i
+		}
+	}
+	color := make(map[string]int)
+	var path []string
+	inCycle := make(map[string]string)
+	var visit func(id string)
+	visit = func(id string) {
+		color[id] = grey
+		path = append(path, id)
+		if dep := m.Traits[index[id]].ParentDependency; dep != nil {
+			for _, c := range dep.condition().comparisons() {
+				if _, ok := index[c.ParentTraitID]; !ok {
+					continue
+				}
+				switch color[c.ParentTraitID] {
+				case white:
+					visit(c.ParentTraitID)
+				case grey:
+					start := 0
+					for k, p := range path {
+						if p == c.ParentTraitID {
+							start = k
+						}
+					}
+					cycle := append(append([]string{}, path[start:]...), c.ParentTraitID)
+					for _, p := range path[start:] {
+						inCycle[p] = strings.Join(cycle, " -> ")
+					}
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		color[id] = black
+	}
+	for _, t := range m.Traits {
+		if t.Type != "derived" && color[t.TraitID] == white {
+			visit(t.TraitID)
+		}
+	}
+	for i, t := range m.Traits {
+		if cycle, ok := inCycle[t.TraitID]; ok && t.Type != "derived" && t.ParentDependency != nil {
+			drop(i, "dependency cycle: "+cycle)
+		}
+	}
+}
+
+func stateIndexOf(states []string, label string) int {
+	for i, s := range states {
+		if strings.EqualFold(s, strings.TrimSpace(label)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// InactiveDependents returns the internal IDs of traits whose #Dependency is
+// contradicted by what the user recorded for the parent traits. Such traits are
+// inapplicable to the specimen: their answers are ignored and they are not
+// recommended. Chains (a -> b -> c) propagate, since a condition on an inactive
+// parent cannot hold, and the derived state children of an inactive nominal
+// parent are inactive too. The observations are expected to be Normalized.
+func (o Observations) InactiveDependents(m *Matrix) map[string]bool {
+	inactive := make(map[string]bool)
+	if m == nil {
+		return inactive
+	}
+	byTraitID := make(map[string]Trait, len(m.Traits))
+	for _, t := range m.Traits {
+		if t.TraitID != "" && t.Type != "derived" {
+			byTraitID[t.TraitID] = t
+		}
+	}
+	env := func(c *depCompare) (bool, bool) {
+		parent, ok := byTraitID[c.ParentTraitID]
+		if !ok {
+			return false, false
+		}
+		if inactive[parent.ID] {
+			return false, true
+		}
+		return o.compareParent(m, parent, c.Op, c.Value)
+	}
+
+	// Repeat until stable, so a trait deactivated late in the sheet still
+	// deactivates the traits that depend on it.
+	for changed := true; changed; {
+		changed = false
+		for _, t := range m.Traits {
+			if inactive[t.ID] || t.ParentDependency == nil {
+				continue
+			}
+			if holds, known := t.ParentDependency.condition().eval(env); known && !holds {
+				inactive[t.ID] = true
+				changed = true
+			}
+		}
+	}
+
+	for _, t := range m.Traits {
+		if t.Type != "derived" {
+			continue
+		}
+		if parent, ok := byTraitID[t.Parent]; ok && inactive[parent.ID] {
+			inactive[t.ID] = true
+		}
+	}
+	return inactive
+}
+
+// compareParent evaluates one comparison against the recorded parent answer.
+func (o Observations) compareParent(m *Matrix, parent Trait, op, value string) (holds, known bool) {
+	switch op {
+	case "=":
+		return o.parentStateMatches(m, parent, value)
+	case "!=":
+		holds, known = o.parentStateMatches(m, parent, value)
+		return !holds && known, known
+	}
+
+	var got, want float64
+	switch parent.Type {
+	case "continuous":
+		v, ok := o.SelectedContinuous[parent.ID]
+		x, err := strconv.ParseFloat(value, 64)
+		if !ok || err != nil {
+			return false, false
+		}
+		got, want = v.Value, x
+	case "nominal_parent":
+		label, ok := o.observedStateLabel(m, parent)
+		gi, wi := stateIndexOf(parent.States, label), stateIndexOf(parent.States, value)
+		if !ok || !parent.Ordinal || gi < 0 || wi < 0 {
+			return false, false
+		}
+		got, want = float64(gi), float64(wi)
+	default:
+		return false, false
+	}
+	switch op {
+	case ">":
+		return got > want, true
+	case ">=":
+		return got >= want, true
+	case "<":
+		return got < want, true
+	case "<=":
+		return got <= want, true
+	}
+	return false, false
+}
+
+// observedStateLabel returns the state chosen for a nominal/ordinal parent, either
+// as a SelectedNominal label or as a positive derived-child answer.
+func (o Observations) observedStateLabel(m *Matrix, parent Trait) (string, bool) {
+	if label, ok := o.SelectedNominal[parent.ID]; ok {
+		return strings.TrimSpace(label), true
+	}
+	for _, c := range m.Traits {
+		if c.Type == "derived" && c.Parent == parent.TraitID && o.Selected[c.ID] == 1 {
+			return c.State, true
+		}
+	}
+	return "", false
+}
+
+// parentStateMatches compares the recorded answer for a dependency parent with the
+// required state. known is false when the parent has not been answered (or the
+// required state cannot be compared), in which case the dependent stays active.
+func (o Observations) parentStateMatches(m *Matrix, parent Trait, required string) (holds, known bool) {
+	required = strings.TrimSpace(required)
+	switch parent.Type {
+	case "binary":
+		v := o.Selected[parent.ID]
+		want := parseTernaryCell(required)
+		if v == 0 || (want != Yes && want != No) {
+			return false, false
+		}
+		return Ternary(v) == want, true
+	case "nominal_parent":
+		if label, ok := o.observedStateLabel(m, parent); ok {
+			return strings.EqualFold(label, required), true
+		}
+		for _, c := range m.Traits {
+			if c.Type == "derived" && c.Parent == parent.TraitID && o.Selected[c.ID] == -1 && strings.EqualFold(c.State, required) {
+				return false, true
+			}
+		}
+	case "categorical_multi":
+		states := o.SelectedMulti[parent.ID]
+		if len(states) == 0 {
+			return false, false
+		}
+		for _, s := range states {
+			if strings.EqualFold(strings.TrimSpace(s), required) {
+				return true, true
+			}
+		}
+		return false, true
+	case "continuous":
+		v, ok := o.SelectedContinuous[parent.ID]
+		want, err := strconv.ParseFloat(required, 64)
+		if !ok || err != nil {
+			return false, false
+		}
+		return math.Abs(v.Value-want) <= math.Abs(v.Error), true
+	}
+	return false, false
+}
@@ -0,0 +1,323 @@
+// backend/engine/dependency_expr.go
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// depExpr is a parsed #Dependency condition. Evaluation is three-valued: known is
+// false while a referenced parent has not been answered, so the dependent trait
+// stays active until the answers actually rule it out.
+type depExpr interface {
+	eval(env depEnv) (holds, known bool)
+	comparisons() []*depCompare
+}
+
+// depEnv looks up the recorded answer for a comparison.
+type depEnv func(c *depCompare) (holds, known bool)
+
+type depAnd struct{ terms []depExpr }
+type depOr struct{ terms []depExpr }
+type depNot struct{ term depExpr }
+
+// depCompare is a single "parent op value" condition. ParentTraitID holds the
+// identifier as written until the loader resolves it to the canonical #TraitID.
+type depCompare struct {
+	ParentTraitID string
+	Op            string // "=", "!=", ">", ">=", "<", "<="
+	Value         string
+}
+
+func (e *depAnd) eval(env depEnv) (bool, bool) {
+	allKnown := true
+	for _, t := range e.terms {
+		holds, known := t.eval(env)
+		if known && !holds {
+			return false, true
+		}
+		allKnown = allKnown && known
+	}
+	return allKnown, allKnown
+}
+
+func (e *depOr) eval(env depEnv) (bool, bool) {
+	allKnown := true
+	for _, t := range e.terms {
+		holds, known := t.eval(env)
+		if known && holds {
+			return true, true
+		}
+		allKnown = allKnown && known
+	}
+	return false, allKnown
+}
+
+func (e *depNot) eval(env depEnv) (bool, bool) {
+	holds, known := e.term.eval(env)
+	return !holds && known, known
+}
+
+func (e *depCompare) eval(env depEnv) (bool, bool) {
+	return env(e)
+}
+
+func (e *depAnd) comparisons() []*depCompare { return collectComparisons(e.terms) }
+func (e *depOr) comparisons() []*depCompare  { return collectComparisons(e.terms) }
+func (e *depNot) comparisons() []*depCompare { return e.term.comparisons() }
+func (e *depCompare) comparisons() []*depCompare {
+	return []*depCompare{e}
+}
+
+func collectComparisons(terms []depExpr) []*depCompare {
+	var out []*depCompare
+	for _, t := range terms {
+		out = append(out, t.comparisons()...)
+	}
+	return out
+}
+
+// parseDependencyExpr parses a #Dependency cell. The grammar is
+//
+//	expr    = and { ("OR" | "||" | "|") and }
+//	and     = unary { ("AND" | "&&" | "&") unary }
+//	unary   = ("NOT" | "!") unary | "(" expr ")" | compare
+//	compare = traitID ["[label]"] op value
+//	op      = "=" | "==" | "!=" | "<>" | ">" | ">=" | "<" | "<="
+//
+// Values containing spaces or operators are written in double quotes.
+func parseDependencyExpr(s string) (depExpr, error) {
+	toks, err := tokenizeDependency(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &depParser{toks: toks}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	return e, nil
+}
+
+type depTokenKind int
+
+const (
+	depTokWord depTokenKind = iota
+	depTokQuoted
+	depTokOp
+	depTokAnd
+	depTokOr
+	depTokNot
+	depTokLParen
+	depTokRParen
+)
+
+type depToken struct {
+	kind depTokenKind
+	text string
+}
+
+func tokenizeDependency(s string) ([]depToken, error) {
+	var toks []depToken
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, depToken{depTokLParen, "("})
+			i++
+		case r == ')':
+			toks = append(toks, depToken{depTokRParen, ")"})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(rs) && rs[end] != '"' {
+				end++
+			}
+			if end >= len(rs) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			toks = append(toks, depToken{depTokQuoted, string(rs[i+1 : end])})
+			i = end + 1
+		case r == '[':
+			// "trait[Label]" is accepted for readability; the label is ignored.
+			end := i + 1
+			for end < len(rs) && rs[end] != ']' {
+				end++
+			}
+			if end >= len(rs) {
+				return nil, fmt.Errorf("unterminated '['")
+			}
+			i = end + 1
+		case strings.ContainsRune("=!<>&|", r):
+			two := ""
+			if i+1 < len(rs) {
+				two = string(rs[i : i+2])
+			}
+			switch two {
+			case "==", "!=", "<>", ">=", "<=":
+				op := two
+				if op == "==" {
+					op = "="
+				} else if op == "<>" {
+					op = "!="
+				}
+				toks = append(toks, depToken{depTokOp, op})
+				i += 2
+				continue
+			case "&&":
+				toks = append(toks, depToken{depTokAnd, two})
+				i += 2
+				continue
+			case "||":
+				toks = append(toks, depToken{depTokOr, two})
+				i += 2
+				continue
+			}
+			switch r {
+			case '&':
+				toks = append(toks, depToken{depTokAnd, "&"})
+			case '|':
+				toks = append(toks, depToken{depTokOr, "|"})
+			case '!':
+				toks = append(toks, depToken{depTokNot, "!"})
+			default:
+				toks = append(toks, depToken{depTokOp, string(r)})
+			}
+			i++
+		default:
+			end := i
+			for end < len(rs) && !unicode.IsSpace(rs[end]) && !strings.ContainsRune(`()"[=!<>&|`, rs[end]) {
+				end++
+			}
+			word := string(rs[i:end])
+			switch strings.ToUpper(word) {
+			case "AND":
+				toks = append(toks, depToken{depTokAnd, word})
+			case "OR":
+				toks = append(toks, depToken{depTokOr, word})
+			case "NOT":
+				toks = append(toks, depToken{depTokNot, word})
+			default:
+				toks = append(toks, depToken{depTokWord, word})
+			}
+			i = end
+		}
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	return toks, nil
+}
+
+type depParser struct {
+	toks []depToken
+	pos  int
+}
+
+func (p *depParser) peek() (depToken, bool) {
+	if p.pos >= len(p.toks) {
+		return depToken{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+func (p *depParser) parseOr() (depExpr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []depExpr{first}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != depTokOr {
+			break
+		}
+		p.pos++
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, next)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return &depOr{terms: terms}, nil
+}
+
+func (p *depParser) parseAnd() (depExpr, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := []depExpr{first}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != depTokAnd {
+			break
+		}
+		p.pos++
+		next, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, next)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return &depAnd{terms: terms}, nil
+}
+
+func (p *depParser) parseUnary() (depExpr, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of condition")
+	}
+	switch t.kind {
+	case depTokNot:
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &depNot{term: inner}, nil
+	case depTokLParen:
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != depTokRParen {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return inner, nil
+	case depTokWord:
+		return p.parseCompare()
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *depParser) parseCompare() (depExpr, error) {
+	parent := p.toks[p.pos].text
+	p.pos++
+	op, ok := p.peek()
+	if !ok || op.kind != depTokOp {
+		return nil, fmt.Errorf("expected a comparison after %q", parent)
+	}
+	p.pos++
+	val, ok := p.peek()
+	if !ok || (val.kind != depTokWord && val.kind != depTokQuoted) {
+		return nil, fmt.Errorf("expected a value after %q %s", parent, op.text)
+	}
+	p.pos++
+	return &depCompare{ParentTraitID: parent, Op: op.text, Value: val.text}, nil
+}
@@ -0,0 +1,203 @@
+// backend/engine/dependency_expr_test.go
+package engine
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// AND binds tighter than OR, NOT binds to the next term, and parentheses
+// override both. Unanswered parents keep the result unknown unless the other
+// terms already decide it.
+func TestDependencyExprPrecedence(t *testing.T) {
+	answers := map[string]string{"a": "yes", "b": "no", "c": "yes"}
+	env := func(c *depCompare) (bool, bool) {
+		v, ok := answers[c.ParentTraitID]
+		if !ok {
+			return false, false
+		}
+		if c.Op == "!=" {
+			return v != c.Value, true
+		}
+		return v == c.Value, true
+	}
+
+	tests := []struct {
+		expr  string
+		holds bool
+		known bool
+	}{
+		{"a=yes OR b=yes AND c=no", true, true},
+		{"(a=yes OR b=yes) AND c=no", false, true},
+		{"c=yes | b=yes & a=no", true, true},
+		{"NOT a=yes OR c=yes", true, true},
+		{"NOT (a=yes OR c=yes)", false, true},
+		{"a=yes && !(b=yes)", true, true},
+		{"a == yes AND b <> yes", true, true},
+		{"z=yes AND a=yes", false, false},
+		{"z=yes AND b=yes", false, true},
+		{"z=yes OR a=yes", true, true},
+		{"z=yes OR b=yes", false, false},
+		{"NOT z=yes", false, false},
+	}
+	for _, tt := range tests {
+		e, err := parseDependencyExpr(tt.expr)
+		if err != nil {
+			t.Errorf("%q: %v", tt.expr, err)
+			continue
+		}
+		holds, known := e.eval(env)
+		if holds != tt.holds || known != tt.known {
+			t.Errorf("%q = (%v, %v), want (%v, %v)", tt.expr, holds, known, tt.holds, tt.known)
+		}
+	}
+}
+
+func TestDependencyExprMalformed(t *testing.T) {
+	for _, s := range []string{"", "a =", "(a = yes", "a = yes)", "a = yes AND", `a = "yes`, "a[label = yes", "NOT", "= yes"} {
+		if _, err := parseDependencyExpr(s); err == nil {
+			t.Errorf("%q parsed without error", s)
+		}
+	}
+}
+
+// Ordered comparisons and != on a continuous parent read the measured value.
+func TestDependencyContinuousComparisons(t *testing.T) {
+	resolver := map[string]string{"len": "len"}
+	m := &Matrix{Traits: []Trait{{ID: "t1", TraitID: "len", Type: "continuous"}}}
+
+	tests := []struct {
+		expr     string
+		inactive bool
+	}{
+		{"len != 5", false},
+		{"len != 4", true},
+		{"len > 3", false},
+		{"len > 4", true},
+		{"len <= 2", true},
+		{"len <= 4", false},
+		{"len >= 4 AND len < 10", false},
+		{"len < 1 OR len > 8", true},
+	}
+	for i, tt := range tests {
+		dep, err := parseDependency(tt.expr, resolver)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.expr, err)
+		}
+		m.Traits = append(m.Traits, Trait{ID: "d" + string(rune('a'+i)), TraitID: "dep" + string(rune('a'+i)), Type: "binary", ParentDependency: dep})
+	}
+
+	if got := (Observations{}).InactiveDependents(m); len(got) != 0 {
+		t.Errorf("unanswered parent deactivated %v", got)
+	}
+	got := Observations{SelectedContinuous: map[string]ContinuousObservation{"t1": {Value: 4}}}.InactiveDependents(m)
+	for i, tt := range tests {
+		if id := m.Traits[i+1].ID; got[id] != tt.inactive {
+			t.Errorf("len = 4, %q: inactive = %v, want %v", tt.expr, got[id], tt.inactive)
+		}
+	}
+}
+
+// A condition on a trait the matrix does not have is never known to fail.
+func TestDependencyUnknownParent(t *testing.T) {
+	if _, err := parseDependency("ghost = yes", map[string]string{"a": "a"}); err == nil {
+		t.Error("parseDependency accepted an unknown parent")
+	}
+	m := &Matrix{Traits: []Trait{
+		{ID: "t1", TraitID: "a", Type: "binary"},
+		{ID: "t2", TraitID: "b", Type: "binary", ParentDependency: &Dependency{ParentTraitID: "ghost", RequiredState: "yes"}},
+	}}
+	obs := Observations{Selected: map[string]int{"t1": -1}}
+	if obs.InactiveDependents(m)["t2"] {
+		t.Error("dependency on an unknown parent deactivated its trait")
+	}
+}
+
+// The loader keeps traits whose #Dependency is malformed, unresolvable,
+// incompatible with the parent type or cyclic, drops the dependency and
+// reports it as a LoadIssue.
+func TestDependencyLoadIssues(t *testing.T) {
+	m := loadTestKey(t, [][]string{
+		{"Length", "len", "continuous", "", "1-5", "3-8"},
+		{"Wings", "wings", "binary", "", "yes", "no"},
+		{"Valid", "valid", "binary", "len > 3 AND wings = yes", "yes", "no"},
+		{"Missing value", "missing", "binary", "len >", "yes", "no"},
+		{"Open paren", "paren", "binary", "(len > 3", "yes", "no"},
+		{"Ghost", "ghost", "binary", "nowhere = yes", "yes", "no"},
+		{"Ordered binary", "ordered", "binary", "wings > 3", "yes", "no"},
+		{"Cycle A", "cyc_a", "binary", "cyc_b = yes", "yes", "no"},
+		{"Cycle B", "cyc_b", "binary", "cyc_a = yes", "yes", "no"},
+	})
+
+	issues := make(map[string]string)
+	for _, is := range m.Issues {
+		if is.Column == "#Dependency" {
+			issues[is.TraitID] = is.Message
+		}
+	}
+	for _, id := range []string{"missing", "paren", "ghost", "ordered", "cyc_a", "cyc_b"} {
+		if _, ok := issues[id]; !ok {
+			t.Errorf("no #Dependency issue for %s (issues: %v)", id, issues)
+		}
+	}
+	if _, ok := issues["valid"]; ok {
+		t.Errorf("valid dependency reported: %s", issues["valid"])
+	}
+	if !strings.Contains(issues["cyc_a"], "cycle") {
+		t.Errorf("cyc_a issue %q does not mention the cycle", issues["cyc_a"])
+	}
+	for _, tr := range m.Traits {
+		_, reported := issues[tr.TraitID]
+		if reported && tr.ParentDependency != nil {
+			t.Errorf("%s kept its dependency after it was reported", tr.TraitID)
+		}
+		if tr.TraitID == "valid" && tr.ParentDependency == nil {
+			t.Error("valid lost its dependency")
+		}
+	}
+}
+
+// loadTestKey writes a two-taxon key (A, B) with the given Traits rows
+// (#Trait_EN, #TraitID, #Type, #Dependency, A, B) and loads it.
+func loadTestKey(t *testing.T, traits [][]string) *Matrix {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	sheets := map[string][][]string{
+		"MatrixInfo": {{"title_en", "Test key"}},
+		"TaxaInfo":   {{"#TaxonID", "#ScientificName"}, {"A", "Alpha"}, {"B", "Beta"}},
+		"Traits":     append([][]string{{"#Trait_EN", "#TraitID", "#Type", "#Dependency", "A", "B"}}, traits...),
+	}
+	if err := f.SetSheetName("Sheet1", "MatrixInfo"); err != nil {
+		t.Fatal(err)
+	}
+	for name, rows := range sheets {
+		if name != "MatrixInfo" {
+			if _, err := f.NewSheet(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for r, row := range rows {
+			cells := make([]any, len(row))
+			for i, v := range row {
+				cells[i] = v
+			}
+			cell, _ := excelize.CoordinatesToCellName(1, r+1)
+			if err := f.SetSheetRow(name, cell, &cells); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	path := filepath.Join(t.TempDir(), "key.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadMatrixExcel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
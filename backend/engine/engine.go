@@ -6,54 +6,76 @@ import (
 	"sort"
 )
 
-func ApplyFiltersAlgoOpt(m *Matrix, selected map[string]int, selectedMulti map[string][]string, mode, algo string, opt AlgoOptions) (*EvalResult, error) {
+func ApplyFiltersAlgoOpt(m *Matrix, obs Observations, mode, algo string, opt AlgoOptions) (*EvalResult, error) {
 	if m == nil {
 		return nil, errors.New("no matrix loaded")
 	}
 
+	obs, unobservable, inactive := obs.Resolve(m, opt.ApplyDependencies)
+	selected, selectedMulti := obs.Selected, obs.SelectedMulti
+
+	scorer, err := LookupScorer(algo)
+	if err != nil {
+		return nil, err
+	}
 	var scores []TaxonScore
-	var post []float64
-	var err error
+	var unknown float64
+	if openSet, ok := scorer.(OpenSetScorer); ok && opt.UnknownPrior > 0 {
+		scores, unknown, err = openSet.ScoreOpenSet(m, obs, opt)
+	} else {
+		scores, err = scorer.Score(m, obs, opt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	probabilistic := scorer.Info().Probabilistic
 
 	taxonIndexMap := make(map[string]int)
 	for i, taxon := range m.Taxa {
 		taxonIndexMap[taxon.ID] = i
 	}
 
-	switch algo {
-	case "heuristic":
-		// Note: Heuristic mode needs updating if it is to support categorical_multi traits.
-		scores, err = evaluateHeuristic(m, selected, mode)
-		if err == nil {
-			post = make([]float64, len(m.Taxa))
-			tempScores := make([]float64, len(m.Taxa))
-			for _, s := range scores {
-				if idx, ok := taxonIndexMap[s.Taxon.ID]; ok {
-					tempScores[idx] = s.Post
-				}
-			}
-			normalize(tempScores)
-			post = tempScores
+	// The normalised scores stand in for the posterior when suggesting traits.
+	post := make([]float64, len(m.Taxa))
+	for _, s := range scores {
+		if idx, ok := taxonIndexMap[s.Taxon.ID]; ok {
+			post[idx] = s.Post
 		}
+	}
+	normalize(post)
 
-	default: // "bayes"
-		var ranked []BayesRanked
-		// Pass the new options to the bayesian evaluator
-		ranked, scores, err = evaluateBayes(m, selected, selectedMulti, opt, mode)
-		if err == nil {
-			post = make([]float64, len(m.Taxa))
-			for _, r := range ranked {
-				if r.Index >= 0 && r.Index < len(post) {
-					post[r.Index] = r.Post
+	// Strict and tolerant modes eliminate taxa with too many conflicts. They are
+	// reported separately, and the remaining posterior is renormalised over the rest.
+	scores, dropped := splitByConflicts(scores, mode, opt)
+	if len(dropped) > 0 {
+		for _, s := range dropped {
+			if idx, ok := taxonIndexMap[s.Taxon.ID]; ok {
+				post[idx] = 0
+			}
+		}
+		if len(scores) == 0 {
+			post = nil // Nothing left to narrow down
+			if unknown > 0 {
+				unknown = 1 // Every taxon in the key was ruled out
+			}
+		} else {
+			normalize(post)
+			if probabilistic {
+				// The unknown class keeps its share of the renormalised mass.
+				mass := unknown
+				for _, s := range scores {
+					mass += s.Post
+				}
+				if mass > 0 {
+					for i := range scores {
+						scores[i].Post /= mass
+					}
+					unknown /= mass
 				}
 			}
 		}
 	}
 
-	if err != nil {
-		return nil, err
-	}
-
 	sort.Slice(scores, func(i, j int) bool {
 		if scores[i].Post == scores[j].Post {
 			return scores[i].Conflicts < scores[j].Conflicts
@@ -83,11 +105,55 @@ func ApplyFiltersAlgoOpt(m *Matrix, selected map[string]int, selectedMulti map[s
 				allSelected[k] = 1 // Mark as selected
 			}
 		}
-		sugg = SuggestTraitsBayes(m, post, tau, allSelected, opt) // Pass combined map
+		for k := range obs.SelectedNominal {
+			allSelected[k] = 1
+		}
+		for k := range obs.SelectedContinuous {
+			allSelected[k] = 1
+		}
+		excluded := make(map[string]bool, len(unobservable)+len(inactive))
+		for k := range unobservable {
+			excluded[k] = true
+		}
+		for k := range inactive {
+			excluded[k] = true
+		}
+		sugg = SuggestTraitsBayes(m, post, tau, allSelected, excluded, opt) // Pass combined map
+	}
+
+	// Probability mass per taxon for the rank summary; similarity scores are
+	// normalised the same way as for suggestions.
+	mass := make(map[string]float64, len(scores))
+	for _, s := range scores {
+		if probabilistic {
+			mass[s.Taxon.ID] = s.Post
+		} else if idx, ok := taxonIndexMap[s.Taxon.ID]; ok && post != nil {
+			mass[s.Taxon.ID] = post[idx]
+		}
+	}
+
+	// The credible set and stopping verdict use the same mass, in ranking order.
+	ranked := make([]float64, 0, len(scores))
+	for _, s := range scores {
+		ranked = append(ranked, mass[s.Taxon.ID])
+	}
+	credible := ComputeCredibleSet(mass, opt.CredibleLevel)
+	verdict := DecideStop(ranked, credible, sugg, opt)
+
+	inactiveIDs := make([]string, 0, len(inactive))
+	for id := range inactive {
+		inactiveIDs = append(inactiveIDs, id)
 	}
+	sort.Strings(inactiveIDs)
 
 	return &EvalResult{
 		Scores:      scores,
 		Suggestions: sugg,
+		Inactive:    inactiveIDs,
+		Dropped:     dropped,
+		Unknown:     unknown,
+		Hierarchy:   AggregateByRank(m, mass, opt.RankThreshold),
+		Credible:    &credible,
+		Verdict:     &verdict,
 	}, nil
 }
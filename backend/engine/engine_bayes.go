@@ -3,11 +3,14 @@ package engine
 
 import (
 	"log"
+	"math"
 	"sort"
 )
 
 // evaluateBayes handles the core logic for Bayesian evaluation.
-func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string][]string, opt AlgoOptions, mode string) ([]BayesRanked, []TaxonScore, error) {
+// With opt.UnknownPrior set it also returns the posterior of the "taxon not in
+// this key" class.
+func evaluateBayes(m *Matrix, obs Observations, opt AlgoOptions) ([]BayesRanked, []TaxonScore, float64, error) {
 	nTaxa := len(m.Taxa)
 
 	traitMap := make(map[string]Trait)
@@ -15,7 +18,13 @@ func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string]
 		traitMap[t.ID] = t
 	}
 
-	activeTraitIDs := make([]string, 0, len(selected)+len(selectedMulti))
+	// Nominal and ordinal traits are answered through a state label or their derived
+	// children, but evaluated as one K-state trait.
+	groups, childParent := buildStateGroups(m)
+	selected, stateObs, stateExcl := collapseStateSelections(groups, childParent, obs.Selected, obs.SelectedNominal)
+	selectedMulti, continuousObs := obs.SelectedMulti, obs.SelectedContinuous
+
+	activeTraitIDs := make([]string, 0, len(selected)+len(selectedMulti)+len(continuousObs)+len(stateObs)+len(stateExcl))
 	for id, val := range selected {
 		if _, ok := traitMap[id]; ok && val != 0 {
 			activeTraitIDs = append(activeTraitIDs, id)
@@ -26,6 +35,17 @@ func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string]
 			activeTraitIDs = append(activeTraitIDs, id)
 		}
 	}
+	for id := range continuousObs {
+		if _, ok := traitMap[id]; ok {
+			activeTraitIDs = append(activeTraitIDs, id)
+		}
+	}
+	for id := range stateObs {
+		activeTraitIDs = append(activeTraitIDs, id)
+	}
+	for id := range stateExcl {
+		activeTraitIDs = append(activeTraitIDs, id)
+	}
 	log.Printf("[Bayes] Active Trait IDs for evaluation: %v", activeTraitIDs)
 
 	// getTruth provides the ground truth data for a given taxon and trait.
@@ -39,6 +59,10 @@ func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string]
 			return BayesTruth{Unknown: true}, false
 		}
 
+		if taxon.Inapplicable[traitID] {
+			return BayesTruth{Inapplicable: true}, true
+		}
+
 		if trait.Type == "categorical_multi" {
 			truthValues, _ := taxon.CategoricalTraits[traitID]
 			log.Printf("[getTruth] For Taxon '%s', Trait '%s', DB value is: %v", taxon.Name, trait.NameEN, truthValues)
@@ -47,7 +71,7 @@ func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string]
 		switch trait.Type {
 		case "continuous":
 			if val, ok := taxon.ContinuousTraits[traitID]; ok {
-				return BayesTruth{Kind: BayesTraitContinuous, Min: val.Min, Max: val.Max}, true
+				return BayesTruth{Kind: BayesTraitContinuous, Min: val.Min, Max: val.Max, Mean: val.Mean, SD: val.SD}, true
 			}
 			return BayesTruth{Kind: BayesTraitContinuous, Unknown: true}, true
 		case "categorical_multi":
@@ -55,11 +79,24 @@ func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string]
 				return BayesTruth{Kind: BayesTraitCategoricalMulti, StatesMulti: values}, true
 			}
 			return BayesTruth{Kind: BayesTraitCategoricalMulti, Unknown: true}, true
+		case "nominal_parent":
+			g, ok := groups[traitID]
+			if !ok {
+				return BayesTruth{Unknown: true}, false
+			}
+			kind := stateTraitKind(trait)
+			if states := g.truthStates(&taxon); len(states) > 0 {
+				return BayesTruth{Kind: kind, K: g.k(), States: states}, true
+			}
+			return BayesTruth{Kind: kind, K: g.k(), Unknown: true}, true
 		default: // binary/derived
 			val, ok := taxon.Traits[traitID]
 			if !ok || val == NA {
 				return BayesTruth{Kind: BayesTraitBinary, K: 2, Unknown: true}, true
 			}
+			if val == Variable {
+				return BayesTruth{Kind: BayesTraitBinary, K: 2, States: []int{1, -1}, Weights: []float64{0.5, 0.5}}, true
+			}
 			return BayesTruth{Kind: BayesTraitBinary, K: 2, States: []int{int(val)}}, true
 		}
 	}
@@ -71,43 +108,43 @@ func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string]
 			return BayesObservation{IsNA: true}, false
 		}
 
+		// A confidence of zero carries no evidence at all, so it is the same as not observing.
+		confidence := observationConfidence(opt, trait)
+		if confidence <= 0 {
+			return BayesObservation{IsNA: true}, false
+		}
+
 		switch trait.Type {
 		case "continuous":
-			if val, ok := selected[traitID]; ok && val != 0 {
-				return BayesObservation{Kind: BayesTraitContinuous, Value: float64(val)}, true
+			if val, ok := continuousObs[traitID]; ok {
+				return BayesObservation{Kind: BayesTraitContinuous, Value: val.Value, ValueError: math.Abs(val.Error), Span: continuousSpan(trait, val.Error), Confidence: confidence}, true
 			}
 		case "categorical_multi":
 			// MODIFIED: Read directly from selectedMulti map
 			if states, ok := selectedMulti[traitID]; ok && len(states) > 0 {
 				log.Printf("[getObs] User selection for trait '%s': %v", trait.NameEN, states)
-				return BayesObservation{Kind: BayesTraitCategoricalMulti, StatesMulti: states}, true
+				return BayesObservation{Kind: BayesTraitCategoricalMulti, StatesMulti: states, Confidence: confidence}, true
+			}
+		case "nominal_parent":
+			if state, ok := stateObs[traitID]; ok {
+				return BayesObservation{Kind: stateTraitKind(trait), K: groups[traitID].k(), State: state, Confidence: confidence}, true
+			}
+			if excl, ok := stateExcl[traitID]; ok {
+				return BayesObservation{Kind: stateTraitKind(trait), K: groups[traitID].k(), Excluded: excl, Confidence: confidence}, true
 			}
 		default: // binary
 			if val, ok := selected[traitID]; ok && val != 0 {
-				return BayesObservation{Kind: BayesTraitBinary, K: 2, State: val}, true
+				return BayesObservation{Kind: BayesTraitBinary, K: 2, State: val, Confidence: confidence}, true
 			}
 		}
 
 		return BayesObservation{IsNA: true}, false
 	}
 
-	// Prepare parameters for the Bayesian evaluation...
-	evalParams := BayesEvalParams{
-		AlphaFP:          opt.DefaultAlphaFP,
-		BetaFN:           opt.DefaultBetaFN,
-		GammaNAPenalty:   opt.GammaNAPenalty,
-		Kappa:            opt.Kappa,
-		EpsilonCut:       1e-6,
-		ConflictPenalty:  opt.ConflictPenalty,
-		ToleranceFactor:  opt.ToleranceFactor,
-		CategoricalAlgo:  opt.CategoricalAlgo,
-		JaccardThreshold: opt.JaccardThreshold,
-	}
-
 	// Call the generic Bayes evaluator
-	post, err := EvalBayesPosteriorGeneric(nTaxa, activeTraitIDs, getTruth, getObs, evalParams)
+	post, unknown, err := EvalBayesPosteriorOpenSet(nTaxa, activeTraitIDs, getTruth, getObs, newBayesEvalParams(m, opt))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	ranked := RankPosterior(post)
@@ -119,7 +156,7 @@ func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string]
 		if r.Index >= 0 && r.Index < len(m.Taxa) {
 			taxon = m.Taxa[r.Index]
 		}
-		matches, support, conflicts := computeMatchStatsGeneric(m, &taxon, selected, selectedMulti, traitMap, opt)
+		matches, support, conflicts := computeMatchStatsGeneric(m, &taxon, selected, selectedMulti, continuousObs, stateObs, stateExcl, groups, traitMap, opt)
 
 		scores[i] = TaxonScore{
 			Index:     r.Index,
@@ -140,12 +177,138 @@ func evaluateBayes(m *Matrix, selected map[string]int, selectedMulti map[string]
 		return scores[i].Post > scores[j].Post
 	})
 
-	return ranked, scores, nil
+	return ranked, scores, unknown, nil
+}
+
+// newBayesEvalParams resolves the Bayes core's parameters, per-trait error rates
+// and priors included, from the request options.
+func newBayesEvalParams(m *Matrix, opt AlgoOptions) BayesEvalParams {
+	traitAlpha, traitBeta := resolveTraitErrorRates(m, opt)
+	return BayesEvalParams{
+		AlphaFP:          opt.DefaultAlphaFP,
+		BetaFN:           opt.DefaultBetaFN,
+		GammaNAPenalty:   opt.GammaNAPenalty,
+		Kappa:            opt.Kappa,
+		EpsilonCut:       1e-6,
+		ConflictPenalty:  opt.ConflictPenalty,
+		ToleranceFactor:  opt.ToleranceFactor,
+		CategoricalAlgo:  opt.CategoricalAlgo,
+		JaccardThreshold: opt.JaccardThreshold,
+		ContinuousAlgo:   opt.ContinuousAlgo,
+		RangeCoverage:    opt.RangeCoverage,
+		Lambda:           opt.Lambda,
+		UnknownPrior:     opt.UnknownPrior,
+		TraitAlphaFP:     traitAlpha,
+		TraitBetaFN:      traitBeta,
+		LogPriors:        resolveLogPriors(m, opt),
+	}
+}
+
+// continuousOverlaps reports whether value ± error falls within the taxon range.
+func continuousOverlaps(obs ContinuousObservation, truth ContinuousValue) bool {
+	e := math.Abs(obs.Error)
+	return obs.Value+e >= truth.Min && obs.Value-e <= truth.Max
+}
+
+// stateTraitKind tells ordinal parents apart from unordered nominal ones.
+func stateTraitKind(t Trait) BayesTraitKind {
+	if t.Ordinal {
+		return BayesTraitOrdinal
+	}
+	return BayesTraitNominal
+}
+
+// resolveTraitErrorRates collects per-trait error rates keyed by internal trait ID.
+// Values declared in the #AlphaFP/#BetaFN columns come first, then the posterior
+// for traits seen in confirmed identifications, under a prior of PriorStrength answers
+// centred on the column value or the global default; request-level maps in opt
+// override both. Request keys may be internal IDs or user-defined #TraitIDs,
+// and a key naming a nominal parent also applies to its derived state children.
+func resolveTraitErrorRates(m *Matrix, opt AlgoOptions) (alpha, beta map[string]float64) {
+	alpha = make(map[string]float64)
+	beta = make(map[string]float64)
+	counts := mergeErrorCounts(CountTraitErrors(m, opt.Confirmed), opt.ErrorCounts)
+	for _, t := range m.Traits {
+		if t.AlphaFP > 0 {
+			alpha[t.ID] = t.AlphaFP
+		}
+		if t.BetaFN > 0 {
+			beta[t.ID] = t.BetaFN
+		}
+		if c, ok := counts[t.ID]; ok {
+			if v, ok := c.posteriorAlpha(priorRate(t.AlphaFP, opt.DefaultAlphaFP, fallbackAlphaFP), opt.PriorStrength); ok {
+				alpha[t.ID] = v
+			}
+			if v, ok := c.posteriorBeta(priorRate(t.BetaFN, opt.DefaultBetaFN, fallbackBetaFN), opt.PriorStrength); ok {
+				beta[t.ID] = v
+			}
+		}
+		if v, ok := traitOptionValue(opt.AlphaFP, t); ok && v > 0 && v < 1 {
+			alpha[t.ID] = v
+		}
+		if v, ok := traitOptionValue(opt.BetaFN, t); ok && v > 0 && v < 1 {
+			beta[t.ID] = v
+		}
+	}
+	return alpha, beta
+}
+
+// traitOptionValue looks up a per-trait request option by internal ID, then by
+// user-defined #TraitID, then (for derived state children) by the parent's #TraitID.
+func traitOptionValue(values map[string]float64, t Trait) (float64, bool) {
+	for _, key := range []string{t.ID, t.TraitID, t.Parent} {
+		if key == "" {
+			continue
+		}
+		if v, ok := values[key]; ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// observationConfidence returns the user's confidence in [0,1] for an answer
+// on the given trait. Traits without an entry in opt.Confidence are certain (1).
+func observationConfidence(opt AlgoOptions, t Trait) float64 {
+	c, ok := traitOptionValue(opt.Confidence, t)
+	if !ok {
+		return 1.0
+	}
+	return math.Max(0, math.Min(1, c))
+}
+
+// resolveLogPriors returns per-taxon log-priors, or nil when every taxon has the same weight.
+// Request-level priors (opt.Priors, keyed by taxon ID) override the matrix #Prior/#Frequency
+// values; taxa without any weight are treated as 1.0 ("common").
+func resolveLogPriors(m *Matrix, opt AlgoOptions) []float64 {
+	weights := make([]float64, len(m.Taxa))
+	flat := true
+	for i, taxon := range m.Taxa {
+		w := taxon.Prior
+		if v, ok := opt.Priors[taxon.ID]; ok && v > 0 {
+			w = v
+		}
+		if w <= 0 {
+			w = 1.0
+		}
+		weights[i] = w
+		if w != weights[0] {
+			flat = false
+		}
+	}
+	if flat {
+		return nil
+	}
+	logPriors := make([]float64, len(weights))
+	for i, w := range weights {
+		logPriors[i] = math.Log(w)
+	}
+	return logPriors
 }
 
 // computeMatchStatsGeneric computes match/support/conflict stats for all trait types.
-func computeMatchStatsGeneric(m *Matrix, taxon *Taxon, selected map[string]int, selectedMulti map[string][]string, traitMap map[string]Trait, opt AlgoOptions) (matches, support, conflicts int) {
-	// Handle binary and continuous traits from 'selected'
+func computeMatchStatsGeneric(m *Matrix, taxon *Taxon, selected map[string]int, selectedMulti map[string][]string, continuousObs map[string]ContinuousObservation, stateObs map[string]int, stateExcl map[string][]int, groups map[string]*stateGroup, traitMap map[string]Trait, opt AlgoOptions) (matches, support, conflicts int) {
+	// Handle binary traits from 'selected'
 	for traitID, obsValue := range selected {
 		if obsValue == 0 {
 			continue
@@ -156,6 +319,10 @@ func computeMatchStatsGeneric(m *Matrix, taxon *Taxon, selected map[string]int,
 		}
 
 		support++
+		if taxon.Inapplicable[traitID] {
+			conflicts++ // Answering an inapplicable trait counts against the taxon
+			continue
+		}
 		isMatch := false
 
 		switch trait.Type {
@@ -164,15 +331,7 @@ func computeMatchStatsGeneric(m *Matrix, taxon *Taxon, selected map[string]int,
 			if !ok || truthValue == NA {
 				continue
 			}
-			if int(truthValue) == obsValue {
-				isMatch = true
-			}
-		case "continuous":
-			truth, ok := taxon.ContinuousTraits[traitID]
-			if !ok {
-				continue
-			}
-			if float64(obsValue) >= truth.Min && float64(obsValue) <= truth.Max {
+			if int(truthValue) == obsValue || truthValue == Variable {
 				isMatch = true
 			}
 		}
@@ -184,12 +343,37 @@ func computeMatchStatsGeneric(m *Matrix, taxon *Taxon, selected map[string]int,
 		}
 	}
 
+	// Continuous measurements match when the taxon range overlaps value ± error.
+	for traitID, obs := range continuousObs {
+		if _, ok := traitMap[traitID]; !ok {
+			continue
+		}
+		support++
+		if taxon.Inapplicable[traitID] {
+			conflicts++ // Answering an inapplicable trait counts against the taxon
+			continue
+		}
+		truth, ok := taxon.ContinuousTraits[traitID]
+		if !ok {
+			continue
+		}
+		if continuousOverlaps(obs, truth) {
+			matches++
+		} else {
+			conflicts++
+		}
+	}
+
 	// MODIFIED: Handle categorical_multi traits directly from 'selectedMulti'
 	for traitID, selectedStates := range selectedMulti {
 		if len(selectedStates) == 0 {
 			continue
 		}
 		support++
+		if taxon.Inapplicable[traitID] {
+			conflicts++ // Answering an inapplicable trait counts against the taxon
+			continue
+		}
 		isMatch := false
 
 		truthStates, ok := taxon.CategoricalTraits[traitID]
@@ -213,5 +397,60 @@ func computeMatchStatsGeneric(m *Matrix, taxon *Taxon, selected map[string]int,
 			conflicts++
 		}
 	}
+
+	// Nominal traits match only on a recorded state; for ordinal traits an
+	// adjacent state is a near miss, not a conflict.
+	for traitID, obsState := range stateObs {
+		g, ok := groups[traitID]
+		if !ok {
+			continue
+		}
+		support++
+		if taxon.Inapplicable[traitID] {
+			conflicts++ // Answering an inapplicable trait counts against the taxon
+			continue
+		}
+		truthStates := g.truthStates(taxon)
+		if len(truthStates) == 0 {
+			continue
+		}
+		minDist := g.k()
+		for _, t := range truthStates {
+			if d := absInt(obsState - t); d < minDist {
+				minDist = d
+			}
+		}
+		near := 0
+		if g.parent.Ordinal {
+			near = 1
+		}
+		if minDist <= near {
+			matches++
+		} else {
+			conflicts++
+		}
+	}
+
+	// Ruling states out matches unless it rules out every recorded state.
+	for traitID, excl := range stateExcl {
+		g, ok := groups[traitID]
+		if !ok {
+			continue
+		}
+		support++
+		if taxon.Inapplicable[traitID] {
+			conflicts++
+			continue
+		}
+		truthStates := g.truthStates(taxon)
+		if len(truthStates) == 0 {
+			continue
+		}
+		if exclusionConflict(truthStates, excl, g.k(), g.parent.Ordinal) {
+			conflicts++
+		} else {
+			matches++
+		}
+	}
 	return
 }
@@ -3,7 +3,6 @@ package engine
 
 import (
 	"errors"
-	"log"
 	"math"
 	"sort"
 )
@@ -19,13 +18,15 @@ const (
 )
 
 type BayesTruth struct {
-	Kind        BayesTraitKind
-	K           int
-	Unknown     bool
-	States      []int
-	Weights     []float64
-	Min, Max    float64
-	StatesMulti []string // For categorical multi
+	Kind         BayesTraitKind
+	K            int
+	Unknown      bool
+	Inapplicable bool // The trait does not apply to the taxon; any answer counts against it
+	States       []int
+	Weights      []float64
+	Min, Max     float64
+	Mean, SD     float64  // Continuous moments; SD 0 means derive them from Min/Max
+	StatesMulti  []string // For categorical multi
 }
 type BayesObservation struct {
 	Kind        BayesTraitKind
@@ -33,9 +34,13 @@ type BayesObservation struct {
 	IsNA        bool
 	State       int
 	Multi       []int
+	Excluded    []int // Nominal/ordinal: states ruled out when no state was chosen
 	MultiW      []float64
 	Value       float64
+	ValueError  float64  // ± measurement error on Value (0 = exact)
+	Span        float64  // Width of the trait's overall range; sets the uninformative density
 	StatesMulti []string // For categorical multi
+	Confidence  float64  // Soft evidence weight in (0,1]; 0 means unset (fully confident)
 }
 
 type BayesTruthGetter func(taxonIdx int, traitID string) (BayesTruth, bool)
@@ -47,6 +52,27 @@ type BayesRanked struct {
 
 const largeNegativeLogLikelihood = -1e6 // Penalty base for conflicts
 
+// Log-likelihoods of an uninformative answer, used as the "don't know" side of soft evidence.
+var (
+	logUninformativeBinary     = math.Log(0.5)
+	logUninformativeContinuous = 0.0 // logProbContinuous scores an in-range value as 0
+)
+
+// softenLogProb mixes an observed-state log-likelihood with the uninformative one
+// according to the observation confidence: log(c·exp(lp) + (1-c)·exp(logU)).
+// A confidence of 1 (or 0, meaning unset) returns lp unchanged.
+func softenLogProb(lp, logU, confidence float64) float64 {
+	if confidence <= 0 || confidence >= 1 {
+		return lp
+	}
+	a := lp + math.Log(confidence)
+	b := logU + math.Log(1-confidence)
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log1p(math.Exp(b-a))
+}
+
 // jaccardSimilarity calculates the Jaccard index between two sets of strings.
 func jaccardSimilarity(set1, set2 []string) float64 {
 	intersectionSize := 0
@@ -88,7 +114,11 @@ func hasIntersection(set1, set2 []string) bool {
 	return false
 }
 
-func logProbContinuous(obsValue float64, truthMin, truthMax float64, toleranceFactor float64) float64 {
+func logProbContinuous(obsValue float64, truthMin, truthMax float64, toleranceFactor, confidence float64) float64 {
+	return softenLogProb(logProbContinuousHard(obsValue, truthMin, truthMax, toleranceFactor), logUninformativeContinuous, confidence)
+}
+
+func logProbContinuousHard(obsValue float64, truthMin, truthMax float64, toleranceFactor float64) float64 {
 	truthRange := truthMax - truthMin
 	if toleranceFactor < 0 {
 		toleranceFactor = 0
@@ -119,7 +149,136 @@ func logProbContinuous(obsValue float64, truthMin, truthMax float64, toleranceFa
 	return largeNegativeLogLikelihood
 }
 
-func logProbBinary(obs int, truth int, alpha, beta, conflictPenaltyFactor float64) float64 {
+// Continuous likelihood models, selected through AlgoOptions.ContinuousAlgo.
+const (
+	ContinuousRange           = "range"            // Flat inside the range, linear penalty in a tolerance band, cliff outside
+	ContinuousGaussian        = "gaussian"         // Normal density from mean/SD, or from Min/Max as a quantile interval
+	ContinuousTruncatedNormal = "truncated_normal" // As gaussian, truncated at zero for non-negative traits
+)
+
+const (
+	defaultRangeCoverage = 0.95
+	minContinuousSD      = 0.05 // Floor for the SD of single-value or very narrow ranges, as in the range model's tolerance
+)
+
+// continuousMoments returns the mean and SD of a taxon's continuous trait. When
+// the matrix gives only a range, it is read as the central `coverage` interval.
+func continuousMoments(truth BayesTruth, coverage float64) (mu, sd float64) {
+	if truth.SD > 0 {
+		return truth.Mean, truth.SD
+	}
+	if coverage <= 0 || coverage >= 1 {
+		coverage = defaultRangeCoverage
+	}
+	mu = (truth.Min + truth.Max) / 2
+	z := math.Sqrt2 * math.Erfinv(coverage)
+	sd = (truth.Max - truth.Min) / (2 * z)
+	return mu, math.Max(sd, minContinuousSD)
+}
+
+// logUniformDensity is the log-density of a value spread evenly over the trait's
+// observed span. It is the reference level for unknown and uncertain normal scores.
+func logUniformDensity(span float64) float64 {
+	if span <= 0 {
+		return 0
+	}
+	return -math.Log(span)
+}
+
+// continuousSpan is the width the uninformative density of a trait spreads over:
+// its overall range, floored at the measurement error (or minContinuousSD) so a
+// trait every taxon records with the same value keeps a finite reference level.
+func continuousSpan(t Trait, measErr float64) float64 {
+	return math.Max(t.MaxValue-t.MinValue, math.Max(math.Abs(measErr), minContinuousSD))
+}
+
+// logProbNormal is the log-density of obs under N(mu, sd² + measErr²). With
+// truncate the density is renormalised to [0, ∞), so taxa near zero are not
+// penalised for mass that no measurement could fall on.
+func logProbNormal(obs, mu, sd, measErr float64, truncate bool, span, confidence float64) float64 {
+	return softenLogProb(logProbNormalHard(obs, mu, sd, measErr, truncate), logUniformDensity(span), confidence)
+}
+
+func logProbNormalHard(obs, mu, sd, measErr float64, truncate bool) float64 {
+	s := math.Sqrt(sd*sd + measErr*measErr)
+	z := (obs - mu) / s
+	lp := -0.5*z*z - math.Log(s) - 0.5*math.Log(2*math.Pi)
+	if truncate {
+		if obs < 0 {
+			return largeNegativeLogLikelihood
+		}
+		mass := 0.5 * math.Erfc(-mu/(s*math.Sqrt2)) // P(X >= 0)
+		if mass > 0 {
+			lp -= math.Log(mass)
+		}
+	}
+	return math.Max(lp, largeNegativeLogLikelihood)
+}
+
+func logProbBinary(obs int, truth int, alpha, beta, conflictPenaltyFactor, confidence float64) float64 {
+	return softenLogProb(logProbBinaryHard(obs, truth, alpha, beta, conflictPenaltyFactor), logUninformativeBinary, confidence)
+}
+
+// logProbInapplicable scores any answer for a trait that does not apply to the
+// taxon. It is treated like a conflict whose base rate is alpha, the chance of
+// recording a structure that is not there.
+func logProbInapplicable(alpha, conflictPenaltyFactor, logU, confidence float64) float64 {
+	hard := largeNegativeLogLikelihood
+	if alpha > 0 {
+		hard = (1-conflictPenaltyFactor)*math.Log(alpha) + conflictPenaltyFactor*largeNegativeLogLikelihood
+	}
+	return softenLogProb(hard, logU, confidence)
+}
+
+// logUninformative returns the "don't know" log-likelihood of an observation, on
+// the same scale as the likelihood of its kind.
+func (p BayesEvalParams) logUninformative(obs BayesObservation) float64 {
+	switch obs.Kind {
+	case BayesTraitNominal, BayesTraitOrdinal:
+		if obs.K > 0 && len(obs.Excluded) > 0 {
+			return math.Log(float64(obs.K-len(obs.Excluded)) / float64(obs.K))
+		}
+		if obs.K > 0 {
+			return -math.Log(float64(obs.K))
+		}
+	case BayesTraitContinuous:
+		if p.ContinuousAlgo == ContinuousGaussian || p.ContinuousAlgo == ContinuousTruncatedNormal {
+			return logUniformDensity(obs.Span)
+		}
+		return logUninformativeContinuous
+	}
+	return logUninformativeBinary
+}
+
+// binaryMixtureProb is P(obs) for a taxon that is Yes with probability pYes,
+// used both for unknown cells (pYes 0.5) and for polymorphic ones.
+func binaryMixtureProb(obs int, pYes, alpha, beta float64) float64 {
+	if obs == 1 {
+		return pYes*(1.0-beta) + (1.0-pYes)*alpha
+	}
+	return pYes*beta + (1.0-pYes)*(1.0-alpha)
+}
+
+// truthPYes returns the probability that a binary truth is Yes: the weight on
+// state 1 for polymorphic truths, 0.5 when no weights are given.
+func truthPYes(truth BayesTruth) float64 {
+	if len(truth.Weights) != len(truth.States) {
+		return 0.5
+	}
+	pYes, total := 0.0, 0.0
+	for i, s := range truth.States {
+		total += truth.Weights[i]
+		if s == 1 {
+			pYes += truth.Weights[i]
+		}
+	}
+	if total <= 0 {
+		return 0.5
+	}
+	return pYes / total
+}
+
+func logProbBinaryHard(obs int, truth int, alpha, beta, conflictPenaltyFactor float64) float64 {
 	obsNorm := 0
 	if obs == 1 {
 		obsNorm = 1
@@ -148,24 +307,143 @@ func logProbBinary(obs int, truth int, alpha, beta, conflictPenaltyFactor float6
 	return 0
 }
 
-func logProbCategoricalMulti(taxonIdx int, obsStates, truthStates []string, algo string, jaccardThreshold float64, alpha, beta, conflictPenalty float64) float64 {
-	var isMatch bool
+// logProbNominal scores a K-state nominal observation: 1-eps on a recorded state,
+// with eps spread evenly over the other K-1 states. Taxa recorded with several
+// states average over them; observing none of them is a conflict.
+func logProbNominal(obs int, truthStates []int, k int, eps, conflictPenaltyFactor, confidence float64) float64 {
+	return softenLogProb(logProbNominalHard(obs, truthStates, k, eps, conflictPenaltyFactor), -math.Log(float64(k)), confidence)
+}
 
-	log.Printf("[Matcher] Taxon %d: Comparing Obs %v WITH Truth %v", taxonIdx, obsStates, truthStates)
+func logProbNominalHard(obs int, truthStates []int, k int, eps, conflictPenaltyFactor float64) float64 {
+	if k < 2 || len(truthStates) == 0 {
+		return 0
+	}
+	pr := 0.0
+	isConflict := true
+	for _, t := range truthStates {
+		pr += nominalStateProb(obs, t, k, eps)
+		if t == obs {
+			isConflict = false
+		}
+	}
+	logPr := math.Log(pr / float64(len(truthStates)))
+	if !isConflict {
+		return logPr
+	}
+	return (1-conflictPenaltyFactor)*logPr + conflictPenaltyFactor*largeNegativeLogLikelihood
+}
 
+// nominalStateProb is P(observed state | true state) for an unordered trait.
+func nominalStateProb(obs, truth, k int, eps float64) float64 {
+	if obs == truth {
+		return 1 - eps
+	}
+	return eps / float64(k-1)
+}
+
+// ordinalStateProb is P(observe state obs | true state truth) for an ordered
+// K-state trait: 1-eps on the true state, with eps spread over the other states
+// so that each further step away is half as likely as the previous one.
+func ordinalStateProb(obs, truth, k int, eps float64) float64 {
+	if obs == truth {
+		return 1 - eps
+	}
+	norm := 0.0
+	for j := 0; j < k; j++ {
+		if j != truth {
+			norm += math.Pow(0.5, float64(absInt(j-truth)-1))
+		}
+	}
+	if norm == 0 {
+		return eps
+	}
+	return eps * math.Pow(0.5, float64(absInt(obs-truth)-1)) / norm
+}
+
+// logProbExclusion scores a nominal/ordinal answer that only rules states out:
+// the probability of reading any of the remaining states, averaged over the
+// recorded states. Unknown taxa get the uninformative share, scaled by the NA
+// penalty; the conflict penalty applies when exclusionConflict holds.
+func (p BayesEvalParams) logProbExclusion(eps float64, truth BayesTruth, obs BayesObservation, ordinal bool) float64 {
+	logU := p.logUninformative(obs)
+	if truth.Unknown {
+		return math.Log(p.GammaNAPenalty) + logU
+	}
+	k := obs.K
+	if k < 2 || len(truth.States) == 0 {
+		return 0
+	}
+	out := make(map[int]bool, len(obs.Excluded))
+	for _, e := range obs.Excluded {
+		out[e] = true
+	}
+	pr := 0.0
+	for _, t := range truth.States {
+		for s := 0; s < k; s++ {
+			if out[s] {
+				continue
+			}
+			if ordinal {
+				pr += ordinalStateProb(s, t, k, eps)
+			} else {
+				pr += nominalStateProb(s, t, k, eps)
+			}
+		}
+	}
+	lp := math.Log(pr / float64(len(truth.States)))
+	if exclusionConflict(truth.States, obs.Excluded, k, ordinal) {
+		lp = (1-p.ConflictPenalty)*lp + p.ConflictPenalty*largeNegativeLogLikelihood
+	}
+	return softenLogProb(lp, logU, obs.Confidence)
+}
+
+// logProbOrdinal scores an ordinal observation against the taxon's recorded
+// state(s), averaging over states when the taxon has more than one. Adjacent
+// states are treated as plausible misreadings; the conflict penalty only applies
+// when every recorded state is two or more steps away from the observation.
+func logProbOrdinal(obs int, truthStates []int, k int, eps, conflictPenaltyFactor, confidence float64) float64 {
+	return softenLogProb(logProbOrdinalHard(obs, truthStates, k, eps, conflictPenaltyFactor), -math.Log(float64(k)), confidence)
+}
+
+func logProbOrdinalHard(obs int, truthStates []int, k int, eps, conflictPenaltyFactor float64) float64 {
+	if k < 2 || len(truthStates) == 0 {
+		return 0
+	}
+	pr := 0.0
+	minDist := k
+	for _, t := range truthStates {
+		pr += ordinalStateProb(obs, t, k, eps)
+		if d := absInt(obs - t); d < minDist {
+			minDist = d
+		}
+	}
+	logPr := math.Log(pr / float64(len(truthStates)))
+	if minDist < 2 {
+		return logPr
+	}
+	return (1-conflictPenaltyFactor)*logPr + conflictPenaltyFactor*largeNegativeLogLikelihood
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func logProbCategoricalMulti(taxonIdx int, obsStates, truthStates []string, algo string, jaccardThreshold float64, alpha, beta, conflictPenalty, confidence float64) float64 {
+	var isMatch bool
 	if algo == "jaccard" {
 		similarity := jaccardSimilarity(obsStates, truthStates)
 		isMatch = similarity >= jaccardThreshold
-		log.Printf("[Matcher] Taxon %d: Jaccard similarity = %.2f, Threshold = %.2f -> isMatch = %t", taxonIdx, similarity, jaccardThreshold, isMatch)
 	} else { // "binary"
 		isMatch = hasIntersection(obsStates, truthStates)
-		log.Printf("[Matcher] Taxon %d: Intersection found -> isMatch = %t", taxonIdx, isMatch)
 	}
 
 	if isMatch {
-		return logProbBinary(1, 1, alpha, beta, conflictPenalty)
+		return logProbBinary(1, 1, alpha, beta, conflictPenalty, confidence)
 	}
-	return logProbBinary(1, 0, alpha, beta, conflictPenalty)
+	return logProbBinary(1, 0, alpha, beta, conflictPenalty, confidence)
 }
 
 func softmaxWithKappa(logPost []float64, kappa, eps float64) []float64 {
@@ -229,6 +507,28 @@ type BayesEvalParams struct {
 	ToleranceFactor  float64
 	CategoricalAlgo  string
 	JaccardThreshold float64
+	ContinuousAlgo   string  // ContinuousRange (default), ContinuousGaussian or ContinuousTruncatedNormal
+	RangeCoverage    float64 // Quantile interval a min-max range stands for under the normal models
+	Lambda           float64 // Likelihood tempering exponent; values <= 0 mean 1 (untempered)
+	UnknownPrior     float64 // Prior of the "taxon not in this key" class; 0 disables it
+	// Per-trait overrides of AlphaFP/BetaFN, keyed by the IDs passed in traitIDs.
+	TraitAlphaFP map[string]float64
+	TraitBetaFN  map[string]float64
+	// LogPriors holds one log-prior per taxon. Nil (or a length mismatch) means a flat prior.
+	LogPriors []float64
+}
+
+// errorRates returns the false-positive/false-negative rates for a trait,
+// falling back to the global AlphaFP/BetaFN when no valid override exists.
+func (p BayesEvalParams) errorRates(traitID string) (alpha, beta float64) {
+	alpha, beta = p.AlphaFP, p.BetaFN
+	if v, ok := p.TraitAlphaFP[traitID]; ok && v > 0 && v < 1 {
+		alpha = v
+	}
+	if v, ok := p.TraitBetaFN[traitID]; ok && v > 0 && v < 1 {
+		beta = v
+	}
+	return alpha, beta
 }
 
 func EvalBayesPosteriorGeneric(
@@ -238,13 +538,40 @@ func EvalBayesPosteriorGeneric(
 	getObs BayesObsGetter,
 	p BayesEvalParams,
 ) ([]float64, error) {
+	post, _, err := EvalBayesPosteriorOpenSet(nTaxa, traitIDs, getTruth, getObs, p)
+	return post, err
+}
+
+// EvalBayesPosteriorOpenSet is EvalBayesPosteriorGeneric with an optional
+// "taxon not in this key" class. When p.UnknownPrior is in (0,1), that class
+// takes the given share of the prior and scores the observations with the
+// background likelihood (backgroundLogLik). unknown is its posterior, and the
+// taxon posteriors then sum to 1-unknown.
+func EvalBayesPosteriorOpenSet(
+	nTaxa int,
+	traitIDs []string,
+	getTruth BayesTruthGetter,
+	getObs BayesObsGetter,
+	p BayesEvalParams,
+) (post []float64, unknown float64, err error) {
 	if nTaxa <= 0 {
-		return nil, errors.New("no taxa")
+		return nil, 0, errors.New("no taxa")
+	}
+	openSet := p.UnknownPrior > 0 && p.UnknownPrior < 1
+	n := nTaxa
+	if openSet {
+		n++ // The last slot holds the unknown class
+	}
+	logPost := make([]float64, n)
+	hasPriors := len(p.LogPriors) == nTaxa
+
+	lambda := p.Lambda
+	if lambda <= 0 {
+		lambda = 1.0
 	}
-	logPost := make([]float64, nTaxa)
 
 	for i := 0; i < nTaxa; i++ {
-		lp := 0.0
+		lp := 0.0 // log-likelihood; the prior is added after tempering
 		for _, tid := range traitIDs {
 			truth, okT := getTruth(i, tid)
 			if !okT {
@@ -254,38 +581,141 @@ func EvalBayesPosteriorGeneric(
 			if !okO || obs.IsNA {
 				continue
 			}
+			lp += p.traitLogLik(i, tid, truth, obs)
+		}
+		// Tempering raises the likelihood to the power lambda: below 1 it flattens the
+		// evidence relative to the prior, above 1 it sharpens it.
+		logPost[i] = lambda * lp
+		if hasPriors {
+			logPost[i] += p.LogPriors[i]
+		}
+	}
+
+	if openSet {
+		// Taxon priors are relative weights; they share 1-UnknownPrior between them.
+		logKnown := math.Log1p(-p.UnknownPrior)
+		if hasPriors {
+			logKnown -= logSumExp(p.LogPriors)
+		} else {
+			logKnown -= math.Log(float64(nTaxa))
+		}
+		for i := 0; i < nTaxa; i++ {
+			logPost[i] += logKnown
+		}
+		logPost[nTaxa] = math.Log(p.UnknownPrior) + lambda*backgroundLogLik(nTaxa, traitIDs, getTruth, getObs, p)
+	}
 
-			switch obs.Kind {
-			case BayesTraitBinary:
-				if truth.Unknown {
-					var pr float64
-					if obs.State == 1 {
-						pr = 0.5*(1.0-p.BetaFN) + 0.5*p.AlphaFP
-					} else {
-						pr = 0.5*(1.0-p.AlphaFP) + 0.5*p.BetaFN
-					}
-					lp += math.Log(p.GammaNAPenalty) + math.Log(pr)
-				} else if len(truth.States) == 1 {
-					lp += logProbBinary(obs.State, truth.States[0], p.AlphaFP, p.BetaFN, p.ConflictPenalty)
-				}
-			case BayesTraitContinuous:
-				if truth.Unknown {
-					lp += math.Log(p.GammaNAPenalty)
-				} else {
-					lp += logProbContinuous(obs.Value, truth.Min, truth.Max, p.ToleranceFactor)
-				}
-			case BayesTraitCategoricalMulti:
-				if truth.Unknown {
-					lp += math.Log(p.GammaNAPenalty)
-				} else {
-					lp += logProbCategoricalMulti(i, obs.StatesMulti, truth.StatesMulti, p.CategoricalAlgo, p.JaccardThreshold, p.AlphaFP, p.BetaFN, p.ConflictPenalty)
-				}
+	post = softmaxWithKappa(logPost, p.Kappa, p.EpsilonCut)
+	if openSet {
+		return post[:nTaxa], post[nTaxa], nil
+	}
+	return post, 0, nil
+}
+
+// traitLogLik is the log-likelihood of one observation given the taxon's truth.
+func (p BayesEvalParams) traitLogLik(i int, tid string, truth BayesTruth, obs BayesObservation) float64 {
+	lp := 0.0
+	alpha, beta := p.errorRates(tid)
+
+	if truth.Inapplicable {
+		// Answering a trait the taxon does not have counts against it; unknown stays neutral.
+		return logProbInapplicable(alpha, p.ConflictPenalty, p.logUninformative(obs), obs.Confidence)
+	}
+
+	switch obs.Kind {
+	case BayesTraitBinary:
+		if truth.Unknown {
+			pr := binaryMixtureProb(obs.State, 0.5, alpha, beta)
+			lp += math.Log(p.GammaNAPenalty) + softenLogProb(math.Log(pr), logUninformativeBinary, obs.Confidence)
+		} else if len(truth.States) == 1 {
+			lp += logProbBinary(obs.State, truth.States[0], alpha, beta, p.ConflictPenalty, obs.Confidence)
+		} else if len(truth.States) > 1 {
+			// Polymorphic: compatible with either answer, never a conflict.
+			pr := binaryMixtureProb(obs.State, truthPYes(truth), alpha, beta)
+			lp += softenLogProb(math.Log(pr), logUninformativeBinary, obs.Confidence)
+		}
+	case BayesTraitContinuous:
+		normal := p.ContinuousAlgo == ContinuousGaussian || p.ContinuousAlgo == ContinuousTruncatedNormal
+		switch {
+		case truth.Unknown && normal:
+			lp += math.Log(p.GammaNAPenalty) + logUniformDensity(obs.Span)
+		case truth.Unknown:
+			lp += math.Log(p.GammaNAPenalty)
+		case normal:
+			mu, sd := continuousMoments(truth, p.RangeCoverage)
+			truncate := p.ContinuousAlgo == ContinuousTruncatedNormal && truth.Min >= 0
+			lp += logProbNormal(obs.Value, mu, sd, obs.ValueError, truncate, obs.Span, obs.Confidence)
+		default:
+			// A measurement error widens the range the value is compatible with.
+			lp += logProbContinuous(obs.Value, truth.Min-obs.ValueError, truth.Max+obs.ValueError, p.ToleranceFactor, obs.Confidence)
+		}
+	case BayesTraitNominal:
+		if len(obs.Excluded) > 0 {
+			lp += p.logProbExclusion(alpha, truth, obs, false)
+		} else if truth.Unknown {
+			lp += math.Log(p.GammaNAPenalty) - math.Log(float64(obs.K))
+		} else {
+			lp += logProbNominal(obs.State, truth.States, obs.K, alpha, p.ConflictPenalty, obs.Confidence)
+		}
+	case BayesTraitOrdinal:
+		if len(obs.Excluded) > 0 {
+			lp += p.logProbExclusion(alpha, truth, obs, true)
+		} else if truth.Unknown {
+			lp += math.Log(p.GammaNAPenalty) - math.Log(float64(obs.K))
+		} else {
+			lp += logProbOrdinal(obs.State, truth.States, obs.K, alpha, p.ConflictPenalty, obs.Confidence)
+		}
+	case BayesTraitCategoricalMulti:
+		if truth.Unknown {
+			lp += math.Log(p.GammaNAPenalty)
+		} else {
+			lp += logProbCategoricalMulti(i, obs.StatesMulti, truth.StatesMulti, p.CategoricalAlgo, p.JaccardThreshold, alpha, beta, p.ConflictPenalty, obs.Confidence)
+		}
+	}
+	return lp
+}
+
+// backgroundLogLik scores the observations under a taxon outside the key. Each
+// trait's answer gets its marginal probability across the matrix (the mean
+// likelihood over the taxa with data), and traits are taken as independent.
+// One uninformative pseudo-taxon is added to every mean, as in add-one
+// smoothing, so a state no taxon in the key shows still has some probability.
+func backgroundLogLik(nTaxa int, traitIDs []string, getTruth BayesTruthGetter, getObs BayesObsGetter, p BayesEvalParams) float64 {
+	total := 0.0
+	for _, tid := range traitIDs {
+		obs, ok := getObs(tid)
+		if !ok || obs.IsNA {
+			continue
+		}
+		lls := []float64{p.logUninformative(obs)}
+		for i := 0; i < nTaxa; i++ {
+			truth, ok := getTruth(i, tid)
+			if !ok || truth.Unknown || truth.Inapplicable {
+				continue
 			}
+			lls = append(lls, p.traitLogLik(i, tid, truth, obs))
+		}
+		total += logSumExp(lls) - math.Log(float64(len(lls)))
+	}
+	return total
+}
+
+// logSumExp returns log(sum(exp(v))) without overflow.
+func logSumExp(v []float64) float64 {
+	m := math.Inf(-1)
+	for _, x := range v {
+		if x > m {
+			m = x
 		}
-		logPost[i] = lp
 	}
-	post := softmaxWithKappa(logPost, p.Kappa, p.EpsilonCut)
-	return post, nil
+	if math.IsInf(m, -1) {
+		return m
+	}
+	sum := 0.0
+	for _, x := range v {
+		sum += math.Exp(x - m)
+	}
+	return m + math.Log(sum)
 }
 
 func RankPosterior(post []float64) []BayesRanked {
@@ -15,7 +15,6 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-var reParentDependency = regexp.MustCompile(`^([a-zA-Z0-9_.-]+)(?:\[.*?\])?\s*=\s*(?:"([^"]+)"|([^"\s=]+))$`)
 var reNormalizeTraitID = regexp.MustCompile(`^[^\[]+`)
 var whitespaceReplacer = strings.NewReplacer("\u00A0", " ", "\t", " ", "　", " ")
 
@@ -25,23 +24,6 @@ func cleanString(s string) string {
 	return strings.TrimSpace(s)
 }
 
-func parseParentDependency(s string) *Dependency {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return nil
-	}
-	matches := reParentDependency.FindStringSubmatch(s)
-	if len(matches) != 4 {
-		log.Printf("[EXCEL PARSER] Invalid dependency format: %s", s)
-		return nil
-	}
-	requiredState := matches[2]
-	if requiredState == "" {
-		requiredState = matches[3]
-	}
-	return &Dependency{ParentTraitID: matches[1], RequiredState: requiredState}
-}
-
 func normalizeTraitID(id string) string {
 	trimmedID := strings.TrimSpace(id)
 	if match := reNormalizeTraitID.FindString(trimmedID); match != "" {
@@ -57,12 +39,19 @@ func sanitizeToID(s string) string {
 	return s
 }
 
+// meanSDRangeZ is the z-score used to derive Min/Max from a "mean±sd" cell,
+// so the stored range is the central 95% interval.
+const meanSDRangeZ = 1.96
+
 func parseRange(s string) (ContinuousValue, bool) {
 	s = cleanString(s)
 	s = strings.ReplaceAll(s, ",", "")
 	if s == "" {
 		return ContinuousValue{}, false
 	}
+	if v, ok := parseMeanSD(s); ok {
+		return v, true
+	}
 	parts := strings.Split(s, "-")
 	if len(parts) == 1 {
 		val, err := strconv.ParseFloat(parts[0], 64)
@@ -84,6 +73,31 @@ func parseRange(s string) (ContinuousValue, bool) {
 	return ContinuousValue{}, false
 }
 
+// parseMeanSD parses "mean±sd" cells (also "mean+-sd" and "mean+/-sd").
+func parseMeanSD(s string) (ContinuousValue, bool) {
+	var parts []string
+	for _, sep := range []string{"±", "+/-", "+-"} {
+		if strings.Contains(s, sep) {
+			parts = strings.SplitN(s, sep, 2)
+			break
+		}
+	}
+	if len(parts) != 2 {
+		return ContinuousValue{}, false
+	}
+	mean, errMean := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	sd, errSD := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errMean != nil || errSD != nil || sd < 0 {
+		return ContinuousValue{}, false
+	}
+	return ContinuousValue{
+		Min:  mean - meanSDRangeZ*sd,
+		Max:  mean + meanSDRangeZ*sd,
+		Mean: mean,
+		SD:   sd,
+	}, true
+}
+
 func toHalfWidthNums(s string) string {
 	repl := strings.NewReplacer(
 		"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
@@ -104,11 +118,23 @@ func parseTernaryCell(s string) Ternary {
 		return No
 	case "1", "y", "yes", "true", "はい", "有り", "あり", "present", "x", "×", "○", "◯", "✓":
 		return Yes
+	case "variable", "polymorphic", "±", "+/-", "1/-1", "-1/1", "1/0", "0/1", "y/n", "yes/no", "present/absent", "変異", "変異あり", "個体差", "多型":
+		return Variable
 	default:
 		return NA
 	}
 }
 
+// isInapplicableCell reports whether a cell marks the trait as inapplicable to the
+// taxon (e.g. venation of a wingless species), which is different from unknown.
+func isInapplicableCell(s string) bool {
+	switch strings.ToLower(cleanString(s)) {
+	case "-", "–", "—", "inapplicable", "not applicable", "非該当", "該当なし":
+		return true
+	}
+	return false
+}
+
 func parseDifficulty(s string) float64 {
 	s = strings.ToLower(cleanString(s))
 	switch s {
@@ -149,6 +175,61 @@ func parseRisk(s string) float64 {
 	}
 }
 
+// parseErrorRate reads an #AlphaFP / #BetaFN cell. Empty or out-of-range values
+// return 0, which means "fall back to the global default".
+func parseErrorRate(s string) float64 {
+	s = cleanString(s)
+	isPercent := strings.HasSuffix(s, "%")
+	s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	if isPercent {
+		v /= 100
+	}
+	if v <= 0 || v >= 1 {
+		return 0
+	}
+	return v
+}
+
+// parsePrior reads a #Prior cell as a relative weight. Non-positive or
+// unparsable values return 0 (no prior information).
+func parsePrior(s string) float64 {
+	s = strings.ReplaceAll(cleanString(s), ",", "")
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return 0
+	}
+	return v
+}
+
+// parseFrequency maps a #Frequency label to a relative prior weight.
+func parseFrequency(s string) float64 {
+	s = strings.ToLower(cleanString(s))
+	switch s {
+	case "abundant", "very common":
+		return 3.0
+	case "common", "普通":
+		return 1.0
+	case "uncommon", "やや稀":
+		return 0.3
+	case "rare", "稀", "まれ":
+		return 0.1
+	case "very rare", "極めて稀":
+		return 0.03
+	default:
+		return parsePrior(s)
+	}
+}
+
 type traitKind int
 
 const (
@@ -223,6 +304,26 @@ func getCell(rows [][]string, r, c int) string {
 	}
 	return rows[r][c]
 }
+
+// optionalCell reads a cell from a column that may be absent from the sheet,
+// returning "" rather than the value in column 0.
+func optionalCell(rows [][]string, r int, header map[string]int, name string) string {
+	col, ok := header[name]
+	if !ok {
+		return ""
+	}
+	return getCell(rows, r, col)
+}
+
+// parseOptionalColumn parses a cell from a column that may be absent from the sheet.
+// A missing column yields the zero value instead of silently reading column 0.
+func parseOptionalColumn(rows [][]string, r int, header map[string]int, name string, parse func(string) float64) float64 {
+	col, ok := header[name]
+	if !ok {
+		return 0
+	}
+	return parse(getCell(rows, r, col))
+}
 func getHeaderMap(header []string) map[string]int {
 	m := make(map[string]int)
 	for i, h := range header {
@@ -320,32 +421,38 @@ func parseTaxaInfo(f *excelize.File) (map[string]*Taxon, error) {
 			continue
 		}
 
-		name := cleanString(getCell(rows, r, header["#scientificname"]))
+		name := cleanString(optionalCell(rows, r, header, "#scientificname"))
+		prior := parseOptionalColumn(rows, r, header, "#prior", parsePrior)
+		if prior == 0 {
+			prior = parseOptionalColumn(rows, r, header, "#frequency", parseFrequency)
+		}
 		taxaMap[taxonID] = &Taxon{
 			ID:                taxonID,
 			Name:              name,
 			ScientificName:    name,
-			Rank:              cleanString(getCell(rows, r, header["#rank"])), // Read the new #Rank column
-			TaxonAuthor:       cleanString(getCell(rows, r, header["#author"])),
-			VernacularNameEN:  cleanString(getCell(rows, r, header["#vernacularname_en"])),
-			VernacularNameJP:  cleanString(getCell(rows, r, header["#vernacularname_ja"])),
-			DescriptionEN:     getCell(rows, r, header["#description_en"]),
-			DescriptionJP:     getCell(rows, r, header["#description_ja"]),
-			Images:            strings.Split(cleanString(getCell(rows, r, header["#images"])), ","),
-			References:        getCell(rows, r, header["#references"]),
-			Order:             cleanString(getCell(rows, r, header["#order"])),
-			Superfamily:       cleanString(getCell(rows, r, header["#superfamily"])),
-			Family:            cleanString(getCell(rows, r, header["#family"])),
-			Subfamily:         cleanString(getCell(rows, r, header["#subfamily"])),
-			Tribe:             cleanString(getCell(rows, r, header["#tribe"])),
-			Subtribe:          cleanString(getCell(rows, r, header["#subtribe"])),
-			Genus:             cleanString(getCell(rows, r, header["#genus"])),
-			Subgenus:          cleanString(getCell(rows, r, header["#subgenus"])),
-			Species:           cleanString(getCell(rows, r, header["#species"])),
-			Subspecies:        cleanString(getCell(rows, r, header["#subspecies"])),
+			Rank:              cleanString(optionalCell(rows, r, header, "#rank")), // Read the new #Rank column
+			TaxonAuthor:       cleanString(optionalCell(rows, r, header, "#author")),
+			VernacularNameEN:  cleanString(optionalCell(rows, r, header, "#vernacularname_en")),
+			VernacularNameJP:  cleanString(optionalCell(rows, r, header, "#vernacularname_ja")),
+			DescriptionEN:     optionalCell(rows, r, header, "#description_en"),
+			DescriptionJP:     optionalCell(rows, r, header, "#description_ja"),
+			Images:            strings.Split(cleanString(optionalCell(rows, r, header, "#images")), ","),
+			References:        optionalCell(rows, r, header, "#references"),
+			Prior:             prior,
+			Order:             cleanString(optionalCell(rows, r, header, "#order")),
+			Superfamily:       cleanString(optionalCell(rows, r, header, "#superfamily")),
+			Family:            cleanString(optionalCell(rows, r, header, "#family")),
+			Subfamily:         cleanString(optionalCell(rows, r, header, "#subfamily")),
+			Tribe:             cleanString(optionalCell(rows, r, header, "#tribe")),
+			Subtribe:          cleanString(optionalCell(rows, r, header, "#subtribe")),
+			Genus:             cleanString(optionalCell(rows, r, header, "#genus")),
+			Subgenus:          cleanString(optionalCell(rows, r, header, "#subgenus")),
+			Species:           cleanString(optionalCell(rows, r, header, "#species")),
+			Subspecies:        cleanString(optionalCell(rows, r, header, "#subspecies")),
 			Traits:            make(map[string]Ternary),
 			ContinuousTraits:  make(map[string]ContinuousValue),
 			CategoricalTraits: make(map[string][]string),
+			Inapplicable:      make(map[string]bool),
 		}
 	}
 	return taxaMap, nil
@@ -436,6 +543,8 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 	}
 	log.Printf("[EXCEL PARSER] Pass 1 complete. Built TraitID resolver map with %d entries.", len(traitIDResolver))
 
+	traitRows := make(map[string]int)
+
 	for r := 1; r < len(rows); r++ {
 		traitNameEN := cleanString(getCell(rows, r, headerMap["#trait_en"]))
 		traitNameJP := cleanString(getCell(rows, r, headerMap["#trait_ja"]))
@@ -464,24 +573,27 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 		}
 
 		var dependency *Dependency
-		depStr := cleanString(getCell(rows, r, headerMap["#dependency"]))
+		depStr := ""
+		if col, ok := headerMap["#dependency"]; ok {
+			depStr = cleanString(getCell(rows, r, col))
+		}
 		if depStr != "" {
-			dep := parseParentDependency(depStr)
-			if dep != nil {
-				parentIdentifier := dep.ParentTraitID
-				resolvedParentID, ok := traitIDResolver[parentIdentifier]
-				if !ok {
-					resolvedParentID, ok = traitIDResolver[sanitizeToID(parentIdentifier)]
-				}
-
-				if ok {
-					dep.ParentTraitID = resolvedParentID
-					dependency = dep
-				} else {
-					log.Printf("[EXCEL PARSER] Warning: Could not resolve dependency parent '%s' for trait '%s'. Dependency will be ignored.", parentIdentifier, traitIdentifier)
-				}
+			dep, err := parseDependency(depStr, traitIDResolver)
+			if err != nil {
+				// The trait is kept without the dependency; the issue is reported with the matrix.
+				matrix.Issues = append(matrix.Issues, LoadIssue{
+					Sheet:   "Traits",
+					Row:     r + 1,
+					TraitID: canonicalTraitID,
+					Column:  "#Dependency",
+					Value:   depStr,
+					Message: err.Error(),
+				})
+			} else {
+				dependency = dep
 			}
 		}
+		traitRows[canonicalTraitID] = r + 1
 
 		spec := parseTypeSpec(cleanString(getCell(rows, r, headerMap["#type"])))
 
@@ -496,6 +608,8 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 			ParentDependency: dependency,
 			Difficulty:       parseDifficulty(cleanString(getCell(rows, r, headerMap["#difficulty"]))),
 			Risk:             parseRisk(cleanString(getCell(rows, r, headerMap["#risk"]))),
+			AlphaFP:          parseOptionalColumn(rows, r, headerMap, "#alphafp", parseErrorRate),
+			BetaFN:           parseOptionalColumn(rows, r, headerMap, "#betafn", parseErrorRate),
 			HelpTextEN:       getCell(rows, r, headerMap["#helptext_en"]),
 			HelpTextJP:       getCell(rows, r, headerMap["#helptext_ja"]),
 			HelpImages:       strings.Split(cleanString(getCell(rows, r, headerMap["#helpimages"])), ","),
@@ -506,7 +620,13 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 			trait.Type = "binary"
 			matrix.Traits = append(matrix.Traits, trait)
 			for i, taxID := range taxonIDs {
-				taxaMap[taxID].Traits[trait.ID] = parseTernaryCell(getCell(rows, r, taxonCols[i]))
+				cell := getCell(rows, r, taxonCols[i])
+				if isInapplicableCell(cell) {
+					taxaMap[taxID].Traits[trait.ID] = NA
+					taxaMap[taxID].Inapplicable[trait.ID] = true
+					continue
+				}
+				taxaMap[taxID].Traits[trait.ID] = parseTernaryCell(cell)
 			}
 
 		case kindNominal, kindOrdinal:
@@ -515,7 +635,7 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 				uniq := map[string]struct{}{}
 				for _, col := range taxonCols {
 					raw := cleanString(getCell(rows, r, col))
-					if raw != "" {
+					if raw != "" && !isInapplicableCell(raw) {
 						uniq[raw] = struct{}{}
 					}
 				}
@@ -525,7 +645,10 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 				sort.Strings(states)
 			}
 
+			// Ordinal traits keep their derived children for the UI, but the engine
+			// evaluates them as a single ordered trait (see Trait.Ordinal).
 			trait.Type = "nominal_parent"
+			trait.Ordinal = spec.kind == kindOrdinal
 			trait.States = states
 			matrix.Traits = append(matrix.Traits, trait)
 
@@ -548,7 +671,9 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 						}
 						return trait.NameJP
 					}(),
-					State: st,
+					State:   st,
+					AlphaFP: trait.AlphaFP,
+					BetaFN:  trait.BetaFN,
 				})
 			}
 
@@ -573,6 +698,12 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 						taxaMap[taxID].Traits[tid] = NA
 					}
 				}
+				if isInapplicableCell(raw) {
+					taxaMap[taxID].Inapplicable[trait.ID] = true
+					for _, tid := range derivedIDs {
+						taxaMap[taxID].Inapplicable[tid] = true
+					}
+				}
 			}
 
 		case kindContinuous:
@@ -581,6 +712,10 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 			hasValues, isInteger := false, true
 
 			for i, taxID := range taxonIDs {
+				if isInapplicableCell(getCell(rows, r, taxonCols[i])) {
+					taxaMap[taxID].Inapplicable[trait.ID] = true
+					continue
+				}
 				if val, ok := parseRange(getCell(rows, r, taxonCols[i])); ok {
 					taxaMap[taxID].ContinuousTraits[trait.ID] = val
 					if val.Min < overallMin {
@@ -611,6 +746,10 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 				if valStr == "" {
 					continue
 				}
+				if isInapplicableCell(valStr) {
+					taxaMap[taxID].Inapplicable[trait.ID] = true
+					continue
+				}
 				parts := strings.Split(valStr, ";")
 				var values []string
 				for _, p := range parts {
@@ -635,5 +774,10 @@ func parseTraits(f *excelize.File, matrix *Matrix, taxaMap map[string]*Taxon) er
 	}
 	log.Printf("[EXCEL PARSER] Pass 2 complete. All traits processed.")
 
+	validateDependencies(matrix, traitRows)
+	for _, issue := range matrix.Issues {
+		log.Printf("[EXCEL PARSER] Warning: %v", issue)
+	}
+
 	return nil
 }
@@ -0,0 +1,228 @@
+// backend/engine/engine_gower.go
+package engine
+
+import (
+	"math"
+	"sort"
+)
+
+// AlgoGower ranks taxa by Gower dissimilarity to the observations. It has no
+// error-rate parameters: each answered trait contributes a partial distance in
+// [0,1], and the taxon distance is their confidence-weighted mean.
+const AlgoGower = "gower"
+
+// evaluateGower scores every taxon by Gower distance over binary, nominal,
+// ordinal, continuous and categorical_multi traits. Cells without data are left
+// out of the mean, as Gower prescribes; cells marked inapplicable are at distance 1.
+// Post holds the similarity 1-D so the usual ranking and normalisation apply.
+func evaluateGower(m *Matrix, obs Observations, opt AlgoOptions) ([]TaxonScore, error) {
+	traitMap := make(map[string]Trait)
+	for _, t := range m.Traits {
+		traitMap[t.ID] = t
+	}
+
+	obs = obs.withoutTraits(unconfidentTraits(m, opt))
+	groups, childParent := buildStateGroups(m)
+	selected, stateObs, stateExcl := collapseStateSelections(groups, childParent, obs.Selected, obs.SelectedNominal)
+	selectedMulti, continuousObs := obs.SelectedMulti, obs.SelectedContinuous
+
+	used := len(stateObs) + len(stateExcl) + len(continuousObs)
+	for _, v := range selected {
+		if v != 0 {
+			used++
+		}
+	}
+	for _, v := range selectedMulti {
+		if len(v) > 0 {
+			used++
+		}
+	}
+
+	scores := make([]TaxonScore, 0, len(m.Taxa))
+	for i := range m.Taxa {
+		taxon := &m.Taxa[i]
+		sum, weight := 0.0, 0.0
+		add := func(t Trait, d float64) {
+			w := observationConfidence(opt, t)
+			sum += w * d
+			weight += w
+		}
+
+		for traitID, v := range selected {
+			t, ok := traitMap[traitID]
+			if !ok || v == 0 || t.Type == "categorical_multi" || t.Type == "continuous" {
+				continue
+			}
+			if taxon.Inapplicable[traitID] {
+				add(t, 1)
+				continue
+			}
+			truth, ok := taxon.Traits[traitID]
+			if !ok || truth == NA {
+				continue
+			}
+			if truth == Variable || int(truth) == v {
+				add(t, 0)
+			} else {
+				add(t, 1)
+			}
+		}
+
+		for traitID, state := range stateObs {
+			g, ok := groups[traitID]
+			if !ok {
+				continue
+			}
+			if taxon.Inapplicable[traitID] {
+				add(g.parent, 1)
+				continue
+			}
+			if d, ok := gowerStateDistance(g, taxon, state); ok {
+				add(g.parent, d)
+			}
+		}
+
+		// Ruled-out states: the distance to the nearest state still allowed.
+		for traitID, excl := range stateExcl {
+			g, ok := groups[traitID]
+			if !ok {
+				continue
+			}
+			if taxon.Inapplicable[traitID] {
+				add(g.parent, 1)
+				continue
+			}
+			if d, ok := gowerExclusionDistance(g, taxon, excl); ok {
+				add(g.parent, d)
+			}
+		}
+
+		for traitID, o := range continuousObs {
+			t, ok := traitMap[traitID]
+			if !ok {
+				continue
+			}
+			if taxon.Inapplicable[traitID] {
+				add(t, 1)
+				continue
+			}
+			if truth, ok := taxon.ContinuousTraits[traitID]; ok {
+				add(t, gowerRangeDistance(o, truth, t.MaxValue-t.MinValue))
+			}
+		}
+
+		for traitID, states := range selectedMulti {
+			t, ok := traitMap[traitID]
+			if !ok || len(states) == 0 {
+				continue
+			}
+			if taxon.Inapplicable[traitID] {
+				add(t, 1)
+				continue
+			}
+			if truth, ok := taxon.CategoricalTraits[traitID]; ok && len(truth) > 0 {
+				add(t, 1-jaccardSimilarity(states, truth))
+			}
+		}
+
+		// A taxon with no comparable cells has nothing in common with the specimen
+		// that can be shown, so it sorts after every taxon that was compared.
+		dist := 0.0
+		switch {
+		case weight > 0:
+			dist = sum / weight
+		case used > 0:
+			dist = 1
+		}
+
+		matches, support, conflicts := computeMatchStatsGeneric(m, taxon, selected, selectedMulti, continuousObs, stateObs, stateExcl, groups, traitMap, opt)
+		scores = append(scores, TaxonScore{
+			Index:     i,
+			Taxon:     *taxon,
+			Post:      1 - dist,
+			Distance:  dist,
+			Used:      used,
+			Conflicts: conflicts,
+			Match:     matches,
+			Support:   support,
+		})
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Distance < scores[j].Distance
+	})
+	if len(scores) > 0 {
+		top := scores[0].Post
+		for i := range scores {
+			scores[i].Delta = top - scores[i].Post
+		}
+	}
+	return scores, nil
+}
+
+// gowerStateDistance is 0/1 for nominal traits and the rank difference scaled
+// by K-1 for ordinal traits, taking the closest state a polymorphic taxon has.
+func gowerStateDistance(g *stateGroup, taxon *Taxon, state int) (float64, bool) {
+	truth := g.truthStates(taxon)
+	if len(truth) == 0 {
+		return 0, false
+	}
+	minDist := g.k()
+	for _, t := range truth {
+		if d := absInt(state - t); d < minDist {
+			minDist = d
+		}
+	}
+	if !g.parent.Ordinal {
+		if minDist == 0 {
+			return 0, true
+		}
+		return 1, true
+	}
+	if g.k() <= 1 {
+		return 0, true
+	}
+	return float64(minDist) / float64(g.k()-1), true
+}
+
+// gowerExclusionDistance is the smallest gowerStateDistance over the states not excluded.
+func gowerExclusionDistance(g *stateGroup, taxon *Taxon, excluded []int) (float64, bool) {
+	out := make(map[int]bool, len(excluded))
+	for _, e := range excluded {
+		out[e] = true
+	}
+	best, found := 1.0, false
+	for s := 0; s < g.k(); s++ {
+		if out[s] {
+			continue
+		}
+		d, ok := gowerStateDistance(g, taxon, s)
+		if !ok {
+			return 0, false
+		}
+		if !found || d < best {
+			best, found = d, true
+		}
+	}
+	return best, found
+}
+
+// gowerRangeDistance is the gap between value ± error and the taxon range,
+// normalised by the trait's overall span (#Min..#Max) and capped at 1.
+func gowerRangeDistance(o ContinuousObservation, truth ContinuousValue, span float64) float64 {
+	e := math.Abs(o.Error)
+	gap := 0.0
+	switch {
+	case o.Value+e < truth.Min:
+		gap = truth.Min - (o.Value + e)
+	case o.Value-e > truth.Max:
+		gap = (o.Value - e) - truth.Max
+	}
+	if gap == 0 {
+		return 0
+	}
+	if span <= 0 {
+		return 1
+	}
+	return math.Min(1, gap/span)
+}
@@ -0,0 +1,62 @@
+// backend/engine/engine_gower_test.go
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func gowerDistances(t *testing.T, m *Matrix, obs Observations) map[string]float64 {
+	t.Helper()
+	res, err := ApplyFiltersAlgoOpt(m, obs, "lenient", AlgoGower, testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string]float64, len(res.Scores))
+	for _, s := range res.Scores {
+		out[s.Taxon.ID] = s.Distance
+	}
+	return out
+}
+
+// An ordinal state counts as (k-1) steps: one step away is half as far as two.
+// Without the ordering every other state is equally far.
+func TestGowerOrdinalScaling(t *testing.T) {
+	m := darknessMatrix()
+	obs := Observations{SelectedNominal: map[string]string{"t1": "dark"}}
+	want := map[string]float64{"DARK": 0, "MEDIUM": 0.5, "PALE": 1}
+	for id, d := range gowerDistances(t, m, obs) {
+		if math.Abs(d-want[id]) > 1e-9 {
+			t.Errorf("ordinal %s: distance %.4f, want %.4f", id, d, want[id])
+		}
+	}
+
+	m.Traits[0].Ordinal = false
+	got := gowerDistances(t, m, obs)
+	if got["MEDIUM"] != 1 || got["PALE"] != 1 {
+		t.Errorf("nominal: MEDIUM %.4f, PALE %.4f, want both 1", got["MEDIUM"], got["PALE"])
+	}
+}
+
+// The gap outside a taxon's range is scaled by the trait's span; with no span
+// any gap is a full mismatch.
+func TestGowerRangeDistance(t *testing.T) {
+	truth := ContinuousValue{Min: 2, Max: 4}
+	tests := []struct {
+		obs  ContinuousObservation
+		span float64
+		want float64
+	}{
+		{ContinuousObservation{Value: 3}, 10, 0},
+		{ContinuousObservation{Value: 6}, 10, 0.2},
+		{ContinuousObservation{Value: 6, Error: 1}, 10, 0.1},
+		{ContinuousObservation{Value: 40}, 10, 1},
+		{ContinuousObservation{Value: 3}, 0, 0},
+		{ContinuousObservation{Value: 4.5}, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := gowerRangeDistance(tt.obs, truth, tt.span); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("value %v±%v, span %v: distance %.4f, want %.4f", tt.obs.Value, tt.obs.Error, tt.span, got, tt.want)
+		}
+	}
+}
@@ -6,46 +6,52 @@ import (
 )
 
 // evaluateHeuristic は単純な一致率でスコアリングします
-func evaluateHeuristic(m *Matrix, selected map[string]int, mode string) ([]TaxonScore, error) {
+// 全ての形質タイプ（二値・連続値・多状態・名義/順序）を computeMatchStatsGeneric で比較し、
+// NA の扱いはベイズ側と揃えます：観察不能・信頼度0の回答は無視し、
+// 分類群側が不明のセルは GammaNAPenalty で割り引いた半一致として数えます。
+func evaluateHeuristic(m *Matrix, obs Observations, opt AlgoOptions) ([]TaxonScore, error) {
 	var scores []TaxonScore
 
-	for _, taxon := range m.Taxa {
-		matches := 0
-		support := 0
-		conflicts := 0
+	traitMap := make(map[string]Trait)
+	for _, t := range m.Traits {
+		traitMap[t.ID] = t
+	}
 
-		for traitID, obsValue := range selected {
-			if obsValue == 0 { // 0は未選択
-				continue
-			}
-			obsTernary := Ternary(obsValue)
-			truthValue, ok := taxon.Traits[traitID]
-			if !ok || truthValue == NA {
-				continue
-			}
+	// 信頼度0の回答は証拠を持たないので、未回答と同じ扱い
+	obs = obs.withoutTraits(unconfidentTraits(m, opt))
 
-			support++
-			if truthValue == obsTernary {
-				matches++
-			} else {
-				conflicts++
-			}
-		}
+	groups, childParent := buildStateGroups(m)
+	selected, stateObs, stateExcl := collapseStateSelections(groups, childParent, obs.Selected, obs.SelectedNominal)
+	selectedMulti, continuousObs := obs.SelectedMulti, obs.SelectedContinuous
 
-		// strictモードでは矛盾があれば除外
-		if mode == "strict" && conflicts > 0 {
-			continue
+	used := len(stateObs) + len(stateExcl) + len(continuousObs)
+	for _, v := range selected {
+		if v != 0 {
+			used++
+		}
+	}
+	for _, v := range selectedMulti {
+		if len(v) > 0 {
+			used++
 		}
+	}
+
+	naCredit := 0.5 * heuristicGamma(opt)
+
+	for _, taxon := range m.Taxa {
+		taxon := taxon
+		matches, support, conflicts := computeMatchStatsGeneric(m, &taxon, selected, selectedMulti, continuousObs, stateObs, stateExcl, groups, traitMap, opt)
+		unknown := support - matches - conflicts // 分類群側のデータが無い形質
 
 		score := 0.0
 		if support > 0 {
-			score = float64(matches) / float64(support)
+			score = (float64(matches) + naCredit*float64(unknown)) / float64(support)
 		}
 
 		scores = append(scores, TaxonScore{
 			Taxon:     taxon,
 			Post:      score, // Postフィールドをヒューリスティックスコアに流用
-			Used:      len(selected),
+			Used:      used,
 			Conflicts: conflicts,
 			Match:     matches,
 			Support:   support,
@@ -68,3 +74,25 @@ func evaluateHeuristic(m *Matrix, selected map[string]int, mode string) ([]Taxon
 
 	return scores, nil
 }
+
+// heuristicGamma はベイズ側と同じ既定値で GammaNAPenalty を解決します
+func heuristicGamma(opt AlgoOptions) float64 {
+	if opt.GammaNAPenalty <= 0 || opt.GammaNAPenalty > 1.0 {
+		return 0.95
+	}
+	return opt.GammaNAPenalty
+}
+
+// unconfidentTraits は信頼度が0に設定された形質のIDを返します
+func unconfidentTraits(m *Matrix, opt AlgoOptions) map[string]bool {
+	if len(opt.Confidence) == 0 {
+		return nil
+	}
+	skip := make(map[string]bool)
+	for _, t := range m.Traits {
+		if observationConfidence(opt, t) <= 0 {
+			skip[t.ID] = true
+		}
+	}
+	return skip
+}
@@ -0,0 +1,43 @@
+// backend/engine/engine_modes.go
+package engine
+
+// Evaluation modes, shared by every algorithm. They decide which taxa are
+// eliminated outright because of conflicting observations.
+const (
+	ModeLenient  = "lenient"  // Keep every taxon; conflicts only lower its score
+	ModeStrict   = "strict"   // Drop any taxon with a conflict
+	ModeTolerant = "tolerant" // Drop taxa with more than AlgoOptions.MaxConflicts conflicts
+)
+
+// conflictLimit returns the largest number of conflicts a taxon may have and
+// still be ranked. ok is false when the mode eliminates nothing.
+func conflictLimit(mode string, opt AlgoOptions) (limit int, ok bool) {
+	switch mode {
+	case ModeStrict:
+		return 0, true
+	case ModeTolerant:
+		if opt.MaxConflicts < 0 {
+			return 0, true
+		}
+		return opt.MaxConflicts, true
+	}
+	return 0, false
+}
+
+// splitByConflicts separates the taxa the mode eliminates from the ones it keeps,
+// preserving order, so that eliminated taxa can be reported instead of vanishing.
+func splitByConflicts(scores []TaxonScore, mode string, opt AlgoOptions) (kept, dropped []TaxonScore) {
+	limit, ok := conflictLimit(mode, opt)
+	if !ok {
+		return scores, nil
+	}
+	kept = make([]TaxonScore, 0, len(scores))
+	for _, s := range scores {
+		if s.Conflicts > limit {
+			dropped = append(dropped, s)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+	return kept, dropped
+}
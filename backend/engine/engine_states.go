@@ -0,0 +1,148 @@
+// backend/engine/engine_states.go
+package engine
+
+import (
+	"sort"
+	"strings"
+)
+
+// stateGroup ties a multi-state (nominal or ordinal) parent trait to the derived
+// Yes/No children the loader creates for each of its states.
+type stateGroup struct {
+	parent   Trait
+	childIDs []string // Derived child IDs, in the parent's state order
+	labels   []string
+}
+
+// buildStateGroups indexes every nominal_parent by internal ID, and maps each
+// derived child ID to its parent's internal ID.
+func buildStateGroups(m *Matrix) (groups map[string]*stateGroup, childParent map[string]string) {
+	groups = make(map[string]*stateGroup)
+	childParent = make(map[string]string)
+	byTraitID := make(map[string]*stateGroup)
+	for _, t := range m.Traits {
+		if t.Type == "nominal_parent" {
+			g := &stateGroup{parent: t}
+			groups[t.ID] = g
+			byTraitID[t.TraitID] = g
+		}
+	}
+	for _, t := range m.Traits {
+		if t.Type != "derived" {
+			continue
+		}
+		g, ok := byTraitID[strings.TrimSpace(t.Parent)]
+		if !ok {
+			continue
+		}
+		g.childIDs = append(g.childIDs, t.ID)
+		g.labels = append(g.labels, t.State)
+		childParent[t.ID] = g.parent.ID
+	}
+	return groups, childParent
+}
+
+// k returns the number of states in the group.
+func (g *stateGroup) k() int {
+	return len(g.childIDs)
+}
+
+// truthStates returns the indices of the states the taxon is recorded with.
+// An empty result means the taxon's state is unknown.
+func (g *stateGroup) truthStates(tx *Taxon) []int {
+	var out []int
+	for i, cid := range g.childIDs {
+		if tx.Traits[cid] == Yes {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// stateIndex returns the index of the state with the given label (case-insensitive).
+func (g *stateGroup) stateIndex(label string) int {
+	label = strings.TrimSpace(label)
+	for i, l := range g.labels {
+		if strings.EqualFold(l, label) {
+			return i
+		}
+	}
+	return -1
+}
+
+// collapseStateSelections gathers the answer for every nominal/ordinal trait into
+// one observation per parent, so that choosing a state is a single K-state
+// observation rather than several correlated binary ones. A state label in
+// selectedNominal wins over derived-child answers. The app answers a state by
+// sending the chosen child as 1 and every sibling as -1, so all child answers
+// are consumed and removed from the returned copy of selected: a parent with a
+// chosen state goes to stateObs, and one with only -1 answers goes to stateExcl
+// as the (sorted) states ruled out.
+func collapseStateSelections(groups map[string]*stateGroup, childParent map[string]string, selected map[string]int, selectedNominal map[string]string) (remaining map[string]int, stateObs map[string]int, stateExcl map[string][]int) {
+	remaining = make(map[string]int, len(selected))
+	stateObs = make(map[string]int)
+	stateExcl = make(map[string][]int)
+	for id, val := range selected {
+		parentID, isChild := childParent[id]
+		if !isChild {
+			remaining[id] = val
+			continue
+		}
+		idx := groups[parentID].childIndex(id)
+		switch val {
+		case 1:
+			stateObs[parentID] = idx
+		case -1:
+			stateExcl[parentID] = append(stateExcl[parentID], idx)
+		}
+	}
+	for parentID, label := range selectedNominal {
+		g, ok := groups[parentID]
+		if !ok {
+			continue
+		}
+		if idx := g.stateIndex(label); idx >= 0 {
+			stateObs[parentID] = idx
+		}
+	}
+	for parentID, excl := range stateExcl {
+		if _, chosen := stateObs[parentID]; chosen || len(excl) >= groups[parentID].k() {
+			delete(stateExcl, parentID) // Ruling out every state says nothing usable
+			continue
+		}
+		sort.Ints(excl)
+	}
+	return remaining, stateObs, stateExcl
+}
+
+// childIndex returns the index of the state held by the derived child id.
+func (g *stateGroup) childIndex(id string) int {
+	for i, cid := range g.childIDs {
+		if cid == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// exclusionConflict reports whether ruling out the excluded states contradicts
+// every recorded state of a taxon. For ordinal traits a state next to one still
+// allowed is a near miss, as for a chosen state.
+func exclusionConflict(truthStates, excluded []int, k int, ordinal bool) bool {
+	near := 0
+	if ordinal {
+		near = 1
+	}
+	out := make(map[int]bool, len(excluded))
+	for _, e := range excluded {
+		out[e] = true
+	}
+	for _, t := range truthStates {
+		for s := 0; s < k; s++ {
+			if !out[s] && absInt(s-t) <= near {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+// backend/engine/engine_states_test.go
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+// darknessMatrix has one ordinal trait (pale < medium < dark) with its derived
+// state children, and one taxon per state.
+func darknessMatrix() *Matrix {
+	m := &Matrix{
+		Traits: []Trait{
+			{ID: "t1", TraitID: "dark", NameEN: "Wing darkness", Type: "nominal_parent", States: []string{"pale", "medium", "dark"}, Ordinal: true},
+			{ID: "t2", NameEN: "Wing darkness = pale", Type: "derived", Parent: "dark", State: "pale"},
+			{ID: "t3", NameEN: "Wing darkness = medium", Type: "derived", Parent: "dark", State: "medium"},
+			{ID: "t4", NameEN: "Wing darkness = dark", Type: "derived", Parent: "dark", State: "dark"},
+		},
+	}
+	for i, id := range []string{"PALE", "MEDIUM", "DARK"} {
+		tx := Taxon{ID: id, Name: id, Traits: map[string]Ternary{}}
+		for j, cid := range []string{"t2", "t3", "t4"} {
+			tx.Traits[cid] = No
+			if i == j {
+				tx.Traits[cid] = Yes
+			}
+		}
+		m.Taxa = append(m.Taxa, tx)
+	}
+	return m
+}
+
+func testOptions() AlgoOptions {
+	return AlgoOptions{DefaultAlphaFP: 0.03, DefaultBetaFN: 0.07, GammaNAPenalty: 0.8, ConflictPenalty: 0.5, ToleranceFactor: 0.1}
+}
+
+func scoresByID(t *testing.T, m *Matrix, obs Observations, mode string) map[string]TaxonScore {
+	t.Helper()
+	res, err := ApplyFiltersAlgoOpt(m, obs, mode, "bayes", testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string]TaxonScore, len(res.Scores))
+	for _, s := range res.Scores {
+		out[s.Taxon.ID] = s
+	}
+	return out
+}
+
+// The app answers a state by sending the chosen child as 1 and every sibling as
+// -1. That must score exactly like the state label, so the ordinal distance
+// model applies and the siblings are not counted again as binary answers.
+func TestCollapseUISelectionMatchesStateLabel(t *testing.T) {
+	m := darknessMatrix()
+	ui := scoresByID(t, m, Observations{Selected: map[string]int{"t2": -1, "t3": -1, "t4": 1}}, "lenient")
+	label := scoresByID(t, m, Observations{SelectedNominal: map[string]string{"t1": "dark"}}, "lenient")
+
+	for id, want := range label {
+		got := ui[id]
+		if math.Abs(got.Post-want.Post) > 1e-9 || got.Conflicts != want.Conflicts {
+			t.Errorf("%s: UI shape gives post %.4f conflicts %d, label gives %.4f conflicts %d", id, got.Post, got.Conflicts, want.Post, want.Conflicts)
+		}
+	}
+	if ui["MEDIUM"].Post <= ui["PALE"].Post {
+		t.Errorf("adjacent MEDIUM (%.4f) should outrank PALE (%.4f)", ui["MEDIUM"].Post, ui["PALE"].Post)
+	}
+	if ui["MEDIUM"].Conflicts != 0 {
+		t.Errorf("adjacent MEDIUM has %d conflicts, want 0", ui["MEDIUM"].Conflicts)
+	}
+
+	strict := scoresByID(t, m, Observations{Selected: map[string]int{"t2": -1, "t3": -1, "t4": 1}}, "strict")
+	if _, ok := strict["MEDIUM"]; !ok {
+		t.Error("strict mode dropped the adjacent MEDIUM")
+	}
+}
+
+// Without a chosen state, -1 answers rule states out as a single observation.
+func TestCollapseExclusionsOnly(t *testing.T) {
+	m := darknessMatrix()
+	groups, childParent := buildStateGroups(m)
+	remaining, stateObs, stateExcl := collapseStateSelections(groups, childParent, map[string]int{"t2": -1, "t3": -1}, nil)
+	if len(remaining) != 0 || len(stateObs) != 0 {
+		t.Fatalf("child answers left over: remaining %v, stateObs %v", remaining, stateObs)
+	}
+	if got := stateExcl["t1"]; len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Fatalf("stateExcl = %v, want [0 1]", got)
+	}
+
+	s := scoresByID(t, m, Observations{Selected: map[string]int{"t2": -1, "t3": -1}}, "lenient")
+	if s["DARK"].Post <= s["MEDIUM"].Post || s["MEDIUM"].Post <= s["PALE"].Post {
+		t.Errorf("want DARK > MEDIUM > PALE, got %.4f %.4f %.4f", s["DARK"].Post, s["MEDIUM"].Post, s["PALE"].Post)
+	}
+	if s["PALE"].Conflicts != 1 || s["MEDIUM"].Conflicts != 0 {
+		t.Errorf("conflicts: PALE %d (want 1), MEDIUM %d (want 0)", s["PALE"].Conflicts, s["MEDIUM"].Conflicts)
+	}
+}
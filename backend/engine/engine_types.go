@@ -1,6 +1,8 @@
 // backend/engine/engine_types.go
 package engine
 
+import "fmt"
+
 // MatrixInfo contains metadata about the entire matrix.
 type MatrixInfo struct {
 	TitleEN       string `json:"title_en"`
@@ -18,24 +20,54 @@ type MatrixInfo struct {
 	ReferencesJP  string `json:"references_jp"`
 }
 
-// Dependency holds parsed dependency rule information.
+// Dependency holds parsed dependency rule information. ParentTraitID and
+// RequiredState are set for a single "parent = state" rule; Expr keeps the full
+// #Dependency condition (AND/OR/NOT, !=, and ordered comparisons).
 type Dependency struct {
 	ParentTraitID string `json:"parentTraitId"`
 	RequiredState string `json:"requiredState"`
+	Expr          string `json:"expr,omitempty"`
+	cond          depExpr
+}
+
+// LoadIssue is a problem found while loading a matrix that did not stop the
+// load, such as a #Dependency that could not be parsed or resolved.
+type LoadIssue struct {
+	Sheet   string `json:"sheet"`
+	Row     int    `json:"row"` // 1-based spreadsheet row (0 = unknown)
+	TraitID string `json:"traitId,omitempty"`
+	Column  string `json:"column,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e LoadIssue) Error() string {
+	return fmt.Sprintf("%s row %d, %s %q: %s", e.Sheet, e.Row, e.Column, e.Value, e.Message)
 }
 
-// ContinuousValue represents a min-max range for a continuous trait.
+// ContinuousValue represents a min-max range for a continuous trait. Cells
+// written as "mean±sd" also carry Mean and SD (SD 0 = range only).
 type ContinuousValue struct {
-	Min float64 `json:"min"`
-	Max float64 `json:"max"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean,omitempty"`
+	SD   float64 `json:"sd,omitempty"`
+}
+
+// ContinuousObservation is a measured value for a continuous trait, with an
+// optional ± measurement error (0 = exact).
+type ContinuousObservation struct {
+	Value float64 `json:"value"`
+	Error float64 `json:"error,omitempty"`
 }
 
 type Ternary int8
 
 const (
-	No  Ternary = -1
-	NA  Ternary = 0
-	Yes Ternary = 1
+	No       Ternary = -1
+	NA       Ternary = 0
+	Yes      Ternary = 1
+	Variable Ternary = 2 // Polymorphic: the taxon varies between individuals, so both answers are expected
 )
 
 type Trait struct {
@@ -52,6 +84,8 @@ type Trait struct {
 	State            string      `json:"state,omitempty"`
 	Difficulty       float64     `json:"difficulty,omitempty"`
 	Risk             float64     `json:"risk,omitempty"`
+	AlphaFP          float64     `json:"alphaFP,omitempty"` // Per-trait false-positive rate from #AlphaFP (0 = use default)
+	BetaFN           float64     `json:"betaFN,omitempty"`  // Per-trait false-negative rate from #BetaFN (0 = use default)
 	HelpTextEN       string      `json:"helpText_en,omitempty"`
 	HelpTextJP       string      `json:"helpText_jp,omitempty"`
 	HelpImages       []string    `json:"helpImages,omitempty"`
@@ -59,6 +93,7 @@ type Trait struct {
 	MaxValue         float64     `json:"maxValue,omitempty"`
 	IsInteger        bool        `json:"isInteger,omitempty"`
 	States           []string    `json:"states,omitempty"`
+	Ordinal          bool        `json:"ordinal,omitempty"` // nominal_parent whose States are ordered (ordinal(a<b<c))
 }
 
 type Taxon struct {
@@ -73,9 +108,11 @@ type Taxon struct {
 	DescriptionJP     string                     `json:"description_ja,omitempty"`
 	Images            []string                   `json:"images,omitempty"`
 	References        string                     `json:"references,omitempty"`
+	Prior             float64                    `json:"prior,omitempty"` // Relative prior weight from #Prior/#Frequency (0 = flat)
 	Traits            map[string]Ternary         `json:"traits"`
 	ContinuousTraits  map[string]ContinuousValue `json:"continuousTraits"`
 	CategoricalTraits map[string][]string        `json:"categoricalTraits"`
+	Inapplicable      map[string]bool            `json:"inapplicable,omitempty"` // Trait IDs that do not apply to this taxon ("-" cells), as opposed to unknown
 	// Taxonomic Ranks
 	Order       string `json:"order,omitempty"`
 	Superfamily string `json:"superfamily,omitempty"`
@@ -90,10 +127,11 @@ type Taxon struct {
 }
 
 type Matrix struct {
-	Name   string     `json:"name"`
-	Info   MatrixInfo `json:"info"`
-	Traits []Trait    `json:"traits"`
-	Taxa   []Taxon    `json:"taxa"`
+	Name   string      `json:"name"`
+	Info   MatrixInfo  `json:"info"`
+	Traits []Trait     `json:"traits"`
+	Taxa   []Taxon     `json:"taxa"`
+	Issues []LoadIssue `json:"issues,omitempty"` // Non-fatal problems found while loading
 }
 
 type TaxonScore struct {
@@ -105,6 +143,7 @@ type TaxonScore struct {
 	Conflicts int     `json:"conflicts"`
 	Match     int     `json:"match"`
 	Support   int     `json:"support"`
+	Distance  float64 `json:"distance,omitempty"` // Gower distance to the observations ("gower" algo only)
 }
 type StateProb struct {
 	State string  `json:"state"`
@@ -123,6 +162,10 @@ type TraitSuggestion struct {
 	Difficulty float64     `json:"difficulty,omitempty"`
 	Risk       float64     `json:"risk,omitempty"`
 	Score      float64     `json:"score"`
+	// Set by the "plan" strategy: expected total #Difficulty and number of
+	// observations to reach the target confidence when this trait is asked next.
+	ExpectedCost  float64 `json:"expectedCost,omitempty"`
+	ExpectedSteps float64 `json:"expectedSteps,omitempty"`
 }
 type AlgoOptions struct {
 	DefaultAlphaFP         float64 `json:"defaultAlphaFP"`
@@ -131,16 +174,53 @@ type AlgoOptions struct {
 	WantInfoGain           bool    `json:"wantInfoGain"`
 	UsePragmaticScore      bool    `json:"usePragmaticScore"`
 	RecommendationStrategy string  `json:"recommendationStrategy"`
-	Lambda                 float64 `json:"lambda"`
-	A0                     float64 `json:"a0"`
-	B0                     float64 `json:"b0"`
+	ApplyDependencies      bool    `json:"applyDependencies"` // Treat traits ruled out by #Dependency as inapplicable
+	Lambda                 float64 `json:"lambda"`            // Likelihood tempering exponent (default 1)
+	PriorStrength          float64 `json:"priorStrength"`     // Weight in answers of the prior on per-trait error rates (default 2)
 	Kappa                  float64 `json:"kappa"`
 	ConflictPenalty        float64 `json:"conflictPenalty"`
 	ToleranceFactor        float64 `json:"toleranceFactor"`
 	CategoricalAlgo        string  `json:"categoricalAlgo"`
 	JaccardThreshold       float64 `json:"jaccardThreshold"`
+	ContinuousAlgo         string  `json:"continuousAlgo"`    // "range" (default), "gaussian" or "truncated_normal"
+	RangeCoverage          float64 `json:"rangeCoverage"`     // Quantile interval a min-max range stands for (default 0.95)
+	MaxConflicts           int     `json:"maxConflicts"`      // Conflicts a taxon may have in the "tolerant" mode
+	DisagreementRanks      int     `json:"disagreementRanks"` // Rank spread that flags a taxon in ensemble mode (default 3)
+	UnknownPrior           float64 `json:"unknownPrior"`      // Prior of a "taxon not in this key" class; 0 disables it
+	RankThreshold          float64 `json:"rankThreshold"`     // Mass a group needs to be "confident at rank X" (default 0.95)
+	CredibleLevel          float64 `json:"credibleLevel"`     // Mass the credible set must cover (default 0.95)
+	StopRatio              float64 `json:"stopRatio"`         // Top-to-second ratio needed to stop (default 10)
+	MinInfoGain            float64 `json:"minInfoGain"`       // Expected IG in bits below which no trait is worth asking (default 0.01)
+	PlanDepth              int     `json:"planDepth"`         // Observations the "plan" strategy looks ahead (default 2, at most 3)
+	// Per-trait error rates keyed by trait ID (internal or #TraitID). They override the matrix columns.
+	AlphaFP map[string]float64 `json:"alphaFP,omitempty"`
+	BetaFN  map[string]float64 `json:"betaFN,omitempty"`
+	// Per-taxon prior weights keyed by taxon ID. They override the matrix #Prior/#Frequency column.
+	Priors map[string]float64 `json:"priors,omitempty"`
+	// Per-observation confidence in [0,1] keyed by trait ID. Missing entries mean full confidence.
+	Confidence map[string]float64 `json:"confidence,omitempty"`
+	// Confirmed identifications used to update per-trait error rates under the PriorStrength prior.
+	Confirmed []ConfirmedID `json:"confirmed,omitempty"`
+	// Answers already tallied by CountTraitErrors, e.g. for stored cases; added to those of Confirmed.
+	ErrorCounts map[string]TraitErrorCounts `json:"-"`
+}
+
+// Observations bundles everything the user has recorded for the specimen being identified.
+type Observations struct {
+	Selected           map[string]int                   `json:"selected"`
+	SelectedMulti      map[string][]string              `json:"selectedMulti"`
+	SelectedNA         map[string]bool                  `json:"selectedNA"`         // Traits the user marked as unobservable
+	SelectedNAGroups   []string                         `json:"selectedNAGroups"`   // Trait groups (EN or JP name) marked as unobservable
+	SelectedNominal    map[string]string                `json:"selectedNominal"`    // One state label per nominal/ordinal trait (ID or #TraitID)
+	SelectedContinuous map[string]ContinuousObservation `json:"selectedContinuous"` // Measurements per continuous trait (ID or #TraitID)
 }
 type EvalResult struct {
 	Scores      []TaxonScore      `json:"scores"`
 	Suggestions []TraitSuggestion `json:"suggestions"`
+	Inactive    []string          `json:"inactive,omitempty"`  // Trait IDs made inapplicable by an observed #Dependency parent
+	Dropped     []TaxonScore      `json:"dropped,omitempty"`   // Taxa eliminated by the strict/tolerant mode
+	Unknown     float64           `json:"unknown,omitempty"`   // Posterior of "taxon not in this key" (AlgoOptions.UnknownPrior)
+	Hierarchy   *Hierarchy        `json:"hierarchy,omitempty"` // Probability summed by taxonomic rank
+	Credible    *CredibleSet      `json:"credible,omitempty"`  // Smallest set of taxa covering AlgoOptions.CredibleLevel
+	Verdict     *StopVerdict      `json:"verdict,omitempty"`   // Whether to stop or keep observing
 }
@@ -0,0 +1,119 @@
+// backend/engine/ensemble.go
+package engine
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// defaultDisagreementRanks is how far apart two algorithms must rank a taxon
+// before it is flagged, unless AlgoOptions.DisagreementRanks says otherwise.
+const defaultDisagreementRanks = 3
+
+// ensembleTop limits disagreement flags to taxa some algorithm ranks this high;
+// reshuffles among implausible taxa are not worth a reviewer's attention.
+const ensembleTop = 10
+
+// EnsembleRun is one algorithm's result within an ensemble evaluation.
+type EnsembleRun struct {
+	Algo   string      `json:"algo"`
+	Result *EvalResult `json:"result"`
+}
+
+// EnsembleEntry compares how the algorithms ranked a single taxon. Ranks are
+// 1-based; a taxon dropped by the strict/tolerant mode ranks after all others.
+type EnsembleEntry struct {
+	Taxon         Taxon              `json:"taxon"`
+	Ranks         map[string]int     `json:"ranks"`
+	Scores        map[string]float64 `json:"scores"`
+	MeanRank      float64            `json:"meanRank"`
+	ConsensusRank int                `json:"consensusRank"`
+	RankSpread    int                `json:"rankSpread"` // Worst rank minus best rank
+	Disagreement  bool               `json:"disagreement"`
+}
+
+// EnsembleResult holds every registered algorithm's ranking side by side,
+// plus the consensus ordering by mean rank.
+type EnsembleResult struct {
+	Runs      []EnsembleRun   `json:"runs"`
+	Consensus []EnsembleEntry `json:"consensus"`
+}
+
+// ApplyEnsemble runs every registered scorer on the same observations and
+// combines their rankings. Suggestions come from each run as usual, except that
+// the plan strategy is only applied to the first run.
+func ApplyEnsemble(m *Matrix, obs Observations, mode string, opt AlgoOptions) (*EnsembleResult, error) {
+	if m == nil {
+		return nil, errors.New("no matrix loaded")
+	}
+	threshold := opt.DisagreementRanks
+	if threshold <= 0 {
+		threshold = defaultDisagreementRanks
+	}
+
+	out := &EnsembleResult{}
+	entries := make(map[string]*EnsembleEntry, len(m.Taxa))
+	for _, t := range m.Taxa {
+		entries[t.ID] = &EnsembleEntry{Taxon: t, Ranks: map[string]int{}, Scores: map[string]float64{}}
+	}
+
+	for n, info := range Algorithms() {
+		runOpt := opt
+		if n > 0 && runOpt.RecommendationStrategy == StrategyPlan {
+			// The look-ahead search is the costly part of a run; only the first
+			// algorithm's suggestions get it, the others keep one-step scores.
+			runOpt.RecommendationStrategy = ""
+		}
+		res, err := ApplyFiltersAlgoOpt(m, obs, mode, info.Name, runOpt)
+		if err != nil {
+			return nil, err
+		}
+		out.Runs = append(out.Runs, EnsembleRun{Algo: info.Name, Result: res})
+
+		// Ties share a rank (1, 2, 2, 4 ...) so equal scores never look like disagreement.
+		for i, s := range res.Scores {
+			rank := i + 1
+			if i > 0 && s.Post == res.Scores[i-1].Post {
+				rank = entries[res.Scores[i-1].Taxon.ID].Ranks[info.Name]
+			}
+			if e, ok := entries[s.Taxon.ID]; ok {
+				e.Ranks[info.Name] = rank
+				e.Scores[info.Name] = s.Post
+			}
+		}
+		for _, s := range res.Dropped {
+			if e, ok := entries[s.Taxon.ID]; ok {
+				e.Ranks[info.Name] = len(res.Scores) + 1
+				e.Scores[info.Name] = 0
+			}
+		}
+	}
+
+	for _, t := range m.Taxa {
+		e := entries[t.ID]
+		best, worst, sum := math.MaxInt, 0, 0
+		for _, r := range e.Ranks {
+			sum += r
+			best = min(best, r)
+			worst = max(worst, r)
+		}
+		if len(e.Ranks) > 0 {
+			e.MeanRank = float64(sum) / float64(len(e.Ranks))
+			e.RankSpread = worst - best
+			e.Disagreement = e.RankSpread >= threshold && best <= ensembleTop
+		}
+		out.Consensus = append(out.Consensus, *e)
+	}
+
+	sort.SliceStable(out.Consensus, func(i, j int) bool {
+		return out.Consensus[i].MeanRank < out.Consensus[j].MeanRank
+	})
+	for i := range out.Consensus {
+		out.Consensus[i].ConsensusRank = i + 1
+		if i > 0 && out.Consensus[i].MeanRank == out.Consensus[i-1].MeanRank {
+			out.Consensus[i].ConsensusRank = out.Consensus[i-1].ConsensusRank
+		}
+	}
+	return out, nil
+}
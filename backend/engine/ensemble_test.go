@@ -0,0 +1,44 @@
+// backend/engine/ensemble_test.go
+package engine
+
+import "testing"
+
+// Taxa that every algorithm scores the same share their rank, and the next
+// taxon skips past them (1, 1, 3), so the tie is never flagged as disagreement.
+func TestEnsembleTieRanks(t *testing.T) {
+	m := &Matrix{Traits: []Trait{
+		{ID: "t1", NameEN: "Wings", Type: "binary"},
+		{ID: "t2", NameEN: "Antennae", Type: "binary"},
+	}}
+	for _, tx := range []struct {
+		id     string
+		t1, t2 Ternary
+	}{{"A", Yes, Yes}, {"B", Yes, Yes}, {"C", No, No}} {
+		m.Taxa = append(m.Taxa, Taxon{ID: tx.id, Name: tx.id, Traits: map[string]Ternary{"t1": tx.t1, "t2": tx.t2}})
+	}
+
+	res, err := ApplyEnsemble(m, Observations{Selected: map[string]int{"t1": 1, "t2": 1}}, "lenient", testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Runs) != len(Algorithms()) {
+		t.Fatalf("%d runs, want one per algorithm (%d)", len(res.Runs), len(Algorithms()))
+	}
+	if len(res.Consensus) != 3 {
+		t.Fatalf("%d consensus entries, want 3", len(res.Consensus))
+	}
+	want := map[string]int{"A": 1, "B": 1, "C": 3}
+	for _, e := range res.Consensus {
+		for algo, rank := range e.Ranks {
+			if rank != want[e.Taxon.ID] {
+				t.Errorf("%s: %s rank %d, want %d", e.Taxon.ID, algo, rank, want[e.Taxon.ID])
+			}
+		}
+		if e.ConsensusRank != want[e.Taxon.ID] {
+			t.Errorf("%s: consensus rank %d, want %d", e.Taxon.ID, e.ConsensusRank, want[e.Taxon.ID])
+		}
+		if e.RankSpread != 0 || e.Disagreement {
+			t.Errorf("%s: spread %d, disagreement %v; want 0, false", e.Taxon.ID, e.RankSpread, e.Disagreement)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// backend/engine/error_prior.go
+package engine
+
+// ConfirmedID is a specimen whose identity has been verified, together with the
+// answers recorded for it. Comparing those answers with the matrix row of the
+// confirmed taxon shows how often each trait is misread.
+type ConfirmedID struct {
+	TaxonID      string       `json:"taxonId"`
+	Observations Observations `json:"observations"`
+}
+
+// TraitErrorCounts tallies confirmed answers on one trait. Negatives are answers
+// on a trait the taxon lacks (the trials for alpha), Positives answers on a trait
+// it has (the trials for beta). For nominal and ordinal traits every answer is a
+// trial for alpha, and a wrong state is a false positive.
+type TraitErrorCounts struct {
+	FalsePositives int `json:"falsePositives"`
+	Negatives      int `json:"negatives"`
+	FalseNegatives int `json:"falseNegatives"`
+	Positives      int `json:"positives"`
+}
+
+// Global error rates used when the options leave them unset, as in EvalBayesPosteriorGeneric.
+const (
+	fallbackAlphaFP = 0.03
+	fallbackBetaFN  = 0.07
+)
+
+// defaultPriorStrength is the weight, in answers, of the prior on a trait's error rate.
+const defaultPriorStrength = 2.0
+
+// betaPrior returns the pseudo-counts of a Beta prior centred on mean with the
+// given strength (default 2): centring on the rate already in use means a few
+// correctly read cases confirm it instead of pulling it towards 0.5.
+func betaPrior(mean, strength float64) (float64, float64) {
+	if strength <= 0 {
+		strength = defaultPriorStrength
+	}
+	return strength * mean, strength * (1 - mean)
+}
+
+// priorRate returns the rate a trait's prior is centred on: its matrix value if
+// set, otherwise the global one, otherwise fallback.
+func priorRate(traitRate, global, fallback float64) float64 {
+	if traitRate > 0 && traitRate < 1 {
+		return traitRate
+	}
+	if global > 0 && global < 1 {
+		return global
+	}
+	return fallback
+}
+
+// posteriorAlpha is the posterior mean of the false-positive rate under a Beta
+// prior centred on mean: (a + errors) / (a + b + trials). ok is false without data.
+func (c TraitErrorCounts) posteriorAlpha(mean, strength float64) (float64, bool) {
+	if c.Negatives == 0 {
+		return 0, false
+	}
+	a, b := betaPrior(mean, strength)
+	return (a + float64(c.FalsePositives)) / (a + b + float64(c.Negatives)), true
+}
+
+// posteriorBeta is the posterior mean of the false-negative rate, as posteriorAlpha.
+func (c TraitErrorCounts) posteriorBeta(mean, strength float64) (float64, bool) {
+	if c.Positives == 0 {
+		return 0, false
+	}
+	a, b := betaPrior(mean, strength)
+	return (a + float64(c.FalseNegatives)) / (a + b + float64(c.Positives)), true
+}
+
+// mergeErrorCounts returns the per-trait sum of a and b, reusing either when the other is empty.
+func mergeErrorCounts(a, b map[string]TraitErrorCounts) map[string]TraitErrorCounts {
+	if len(b) == 0 {
+		return a
+	}
+	if len(a) == 0 {
+		return b
+	}
+	out := make(map[string]TraitErrorCounts, len(a)+len(b))
+	for id, c := range a {
+		out[id] = c
+	}
+	for id, c := range b {
+		n := out[id]
+		n.FalsePositives += c.FalsePositives
+		n.Negatives += c.Negatives
+		n.FalseNegatives += c.FalseNegatives
+		n.Positives += c.Positives
+		out[id] = n
+	}
+	return out
+}
+
+// CountTraitErrors compares each confirmed identification with the matrix and
+// tallies the answers per trait (internal ID). Binary and nominal/ordinal traits
+// are counted; cells that are unknown, polymorphic or inapplicable carry no
+// information about misreading and are skipped, as are unknown taxa.
+func CountTraitErrors(m *Matrix, confirmed []ConfirmedID) map[string]TraitErrorCounts {
+	if m == nil || len(confirmed) == 0 {
+		return nil
+	}
+	taxa := make(map[string]*Taxon, len(m.Taxa))
+	for i := range m.Taxa {
+		taxa[m.Taxa[i].ID] = &m.Taxa[i]
+	}
+	traitMap := make(map[string]Trait, len(m.Traits))
+	for _, t := range m.Traits {
+		traitMap[t.ID] = t
+	}
+	groups, childParent := buildStateGroups(m)
+
+	counts := make(map[string]TraitErrorCounts)
+	for _, c := range confirmed {
+		taxon, ok := taxa[c.TaxonID]
+		if !ok {
+			continue
+		}
+		// The confirmed taxon follows the key's #Dependency rules, whatever the
+		// current setting, so answers its dependents cannot have are skipped.
+		obs, _, _ := c.Observations.Resolve(m, true)
+		selected, stateObs, _ := collapseStateSelections(groups, childParent, obs.Selected, obs.SelectedNominal)
+
+		for traitID, v := range selected {
+			t, ok := traitMap[traitID]
+			if !ok || v == 0 || (t.Type != "binary" && t.Type != "derived") || taxon.Inapplicable[traitID] {
+				continue
+			}
+			n := counts[traitID]
+			switch taxon.Traits[traitID] {
+			case No:
+				n.Negatives++
+				if v == int(Yes) {
+					n.FalsePositives++
+				}
+			case Yes:
+				n.Positives++
+				if v == int(No) {
+					n.FalseNegatives++
+				}
+			default:
+				continue
+			}
+			counts[traitID] = n
+		}
+
+		for traitID, state := range stateObs {
+			g, ok := groups[traitID]
+			if !ok || taxon.Inapplicable[traitID] {
+				continue
+			}
+			truth := g.truthStates(taxon)
+			if len(truth) != 1 {
+				continue
+			}
+			n := counts[traitID]
+			n.Negatives++
+			if truth[0] != state {
+				n.FalsePositives++
+			}
+			counts[traitID] = n
+		}
+	}
+	return counts
+}
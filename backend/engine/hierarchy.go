@@ -0,0 +1,169 @@
+// backend/engine/hierarchy.go
+package engine
+
+import (
+	"sort"
+	"strings"
+)
+
+// TaxonomicRanks lists the rank columns of the Taxa sheet from the top down.
+var TaxonomicRanks = []string{
+	"order", "superfamily", "family", "subfamily", "tribe",
+	"subtribe", "genus", "subgenus", "species", "subspecies",
+}
+
+// defaultRankThreshold is the probability mass a group needs before the result
+// is called confident at its rank, unless AlgoOptions.RankThreshold is set.
+const defaultRankThreshold = 0.95
+
+// RankNode is one group in the taxonomic tree, with the summed probability of
+// the taxa below it. Leaves are the matrix rows themselves (Rank "taxon").
+type RankNode struct {
+	Rank     string      `json:"rank"`
+	Name     string      `json:"name"`
+	TaxonID  string      `json:"taxonId,omitempty"` // Set on leaves only
+	Post     float64     `json:"post"`
+	Count    int         `json:"count"` // Matrix rows below this node
+	Children []*RankNode `json:"children,omitempty"`
+}
+
+// RankConfidence is the lowest rank at which a single group holds at least
+// Threshold of the probability mass.
+type RankConfidence struct {
+	Rank      string  `json:"rank"`
+	Name      string  `json:"name"`
+	Post      float64 `json:"post"`
+	Threshold float64 `json:"threshold"`
+}
+
+// Hierarchy is the ranking aggregated up the taxonomic ranks.
+type Hierarchy struct {
+	Roots []*RankNode `json:"roots"`
+	// Confident is nil when no group reaches the threshold, not even at the top rank.
+	Confident *RankConfidence `json:"confident,omitempty"`
+}
+
+// rankValue returns the taxon's name at the given rank. Species and subspecies
+// epithets are prefixed with the genus so that the label stands on its own.
+func (t *Taxon) rankValue(rank string) string {
+	var v string
+	switch rank {
+	case "order":
+		v = t.Order
+	case "superfamily":
+		v = t.Superfamily
+	case "family":
+		v = t.Family
+	case "subfamily":
+		v = t.Subfamily
+	case "tribe":
+		v = t.Tribe
+	case "subtribe":
+		v = t.Subtribe
+	case "genus":
+		v = t.Genus
+	case "subgenus":
+		v = t.Subgenus
+	case "species":
+		if t.Species != "" {
+			v = strings.TrimSpace(t.Genus + " " + t.Species)
+		}
+	case "subspecies":
+		if t.Subspecies != "" {
+			v = strings.TrimSpace(t.Genus + " " + t.Species + " " + t.Subspecies)
+		}
+	}
+	return strings.TrimSpace(v)
+}
+
+// AggregateByRank sums the probability mass of each taxon (keyed by taxon ID)
+// up the taxonomic ranks. Ranks a taxon leaves blank are skipped for that taxon,
+// so a genus without subgenera hangs its species directly below the genus.
+// threshold <= 0 selects defaultRankThreshold.
+func AggregateByRank(m *Matrix, mass map[string]float64, threshold float64) *Hierarchy {
+	if threshold <= 0 {
+		threshold = defaultRankThreshold
+	}
+	h := &Hierarchy{}
+	index := make(map[*RankNode]map[string]*RankNode) // children by rank and name
+	root := &RankNode{}
+	child := func(parent *RankNode, rank, name string) *RankNode {
+		kids := index[parent]
+		if kids == nil {
+			kids = make(map[string]*RankNode)
+			index[parent] = kids
+		}
+		key := rank + "\x00" + name
+		n, ok := kids[key]
+		if !ok {
+			n = &RankNode{Rank: rank, Name: name}
+			kids[key] = n
+			parent.Children = append(parent.Children, n)
+		}
+		return n
+	}
+
+	// Mass per group at every rank, for the confidence summary.
+	byRank := make(map[string]map[string]float64)
+	names := make(map[string]string) // Display names of the leaves, keyed by taxon ID
+	for i := range m.Taxa {
+		t := &m.Taxa[i]
+		p := mass[t.ID]
+		node := root
+		for _, rank := range TaxonomicRanks {
+			name := t.rankValue(rank)
+			if name == "" {
+				continue
+			}
+			node = child(node, rank, name)
+			node.Post += p
+			node.Count++
+			if byRank[rank] == nil {
+				byRank[rank] = make(map[string]float64)
+			}
+			byRank[rank][name] += p
+		}
+		leaf := child(node, "taxon", t.ID)
+		leaf.Name = t.Name
+		leaf.TaxonID = t.ID
+		leaf.Post += p
+		leaf.Count++
+		if byRank["taxon"] == nil {
+			byRank["taxon"] = make(map[string]float64)
+		}
+		byRank["taxon"][t.ID] += p
+		names[t.ID] = t.Name
+	}
+	sortRankNodes(root.Children)
+	h.Roots = root.Children
+
+	ranks := append(append([]string(nil), TaxonomicRanks...), "taxon")
+	for i := len(ranks) - 1; i >= 0; i-- {
+		rank := ranks[i]
+		for name, p := range byRank[rank] {
+			if p >= threshold && (h.Confident == nil || p > h.Confident.Post) {
+				if rank == "taxon" {
+					name = names[name]
+				}
+				h.Confident = &RankConfidence{Rank: rank, Name: name, Post: p, Threshold: threshold}
+			}
+		}
+		if h.Confident != nil {
+			break
+		}
+	}
+	return h
+}
+
+// sortRankNodes orders every level by descending probability, then by name.
+func sortRankNodes(nodes []*RankNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Post != nodes[j].Post {
+			return nodes[i].Post > nodes[j].Post
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, n := range nodes {
+		sortRankNodes(n.Children)
+	}
+}
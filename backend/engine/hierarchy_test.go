@@ -0,0 +1,48 @@
+// backend/engine/hierarchy_test.go
+package engine
+
+import "testing"
+
+// The confident rank is the lowest one at which a single group reaches the
+// threshold: a split between two congeners is confident at genus, not family.
+func TestAggregateByRankConfident(t *testing.T) {
+	m := &Matrix{Taxa: []Taxon{
+		{ID: "A", Name: "Apis mellifera", Family: "Apidae", Genus: "Apis", Species: "mellifera"},
+		{ID: "B", Name: "Apis cerana", Family: "Apidae", Genus: "Apis", Species: "cerana"},
+		{ID: "C", Name: "Bombus terrestris", Family: "Apidae", Genus: "Bombus", Species: "terrestris"},
+		{ID: "D", Name: "Vespa mandarinia", Family: "Vespidae", Genus: "Vespa", Species: "mandarinia"},
+	}}
+
+	tests := []struct {
+		name string
+		mass map[string]float64
+		rank string
+		at   string
+	}{
+		{"one taxon", map[string]float64{"A": 0.97, "B": 0.02, "C": 0.01}, "taxon", "Apis mellifera"},
+		{"two congeners", map[string]float64{"A": 0.5, "B": 0.47, "C": 0.03}, "genus", "Apis"},
+		{"one family", map[string]float64{"A": 0.4, "B": 0.3, "C": 0.3}, "family", "Apidae"},
+		{"two families", map[string]float64{"A": 0.5, "D": 0.5}, "", ""},
+	}
+	for _, tt := range tests {
+		h := AggregateByRank(m, tt.mass, 0.95)
+		if tt.rank == "" {
+			if h.Confident != nil {
+				t.Errorf("%s: confident at %s %s, want none", tt.name, h.Confident.Rank, h.Confident.Name)
+			}
+			continue
+		}
+		if h.Confident == nil {
+			t.Errorf("%s: no confident rank, want %s %s", tt.name, tt.rank, tt.at)
+			continue
+		}
+		if h.Confident.Rank != tt.rank || h.Confident.Name != tt.at {
+			t.Errorf("%s: confident at %s %s, want %s %s", tt.name, h.Confident.Rank, h.Confident.Name, tt.rank, tt.at)
+		}
+	}
+
+	h := AggregateByRank(m, map[string]float64{"A": 0.5, "B": 0.47, "C": 0.03}, 0.95)
+	if len(h.Roots) != 2 || h.Roots[0].Name != "Apidae" || h.Roots[0].Count != 3 {
+		t.Errorf("roots: got %d, first %+v; want Apidae (3 taxa) first of 2", len(h.Roots), h.Roots[0])
+	}
+}
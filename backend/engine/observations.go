@@ -0,0 +1,157 @@
+// backend/engine/observations.go
+package engine
+
+import "strings"
+
+// Unobservable expands SelectedNA and SelectedNAGroups into the set of internal
+// trait IDs that cannot be observed on the current specimen. A 0 answer in
+// Selected, which is what the app's NA button records, counts as such a mark.
+// Marking a nominal parent (or its whole group, or any of its derived state
+// children) also marks its derived state children.
+func (o Observations) Unobservable(m *Matrix) map[string]bool {
+	out := make(map[string]bool)
+	if m == nil {
+		return out
+	}
+	groups := make(map[string]bool, len(o.SelectedNAGroups))
+	for _, g := range o.SelectedNAGroups {
+		if g = strings.TrimSpace(g); g != "" {
+			groups[strings.ToLower(g)] = true
+		}
+	}
+	parents := make(map[string]bool)
+	for _, t := range m.Traits {
+		if v, ok := o.Selected[t.ID]; ok && v == 0 && t.Type == "derived" {
+			parents[t.Parent] = true
+		}
+	}
+	for _, t := range m.Traits {
+		marked := o.SelectedNA[t.ID]
+		if v, ok := o.Selected[t.ID]; ok && v == 0 {
+			marked = true
+		}
+		if t.Type == "nominal_parent" && parents[t.TraitID] {
+			marked = true
+		}
+		if t.TraitID != "" && o.SelectedNA[t.TraitID] {
+			marked = true
+		}
+		if groups[strings.ToLower(t.GroupEN)] || groups[strings.ToLower(t.GroupJP)] {
+			marked = true
+		}
+		if marked {
+			out[t.ID] = true
+			if t.Type == "nominal_parent" {
+				parents[t.TraitID] = true
+			}
+		}
+	}
+	for _, t := range m.Traits {
+		if t.Type == "derived" && parents[t.Parent] {
+			out[t.ID] = true
+		}
+	}
+	return out
+}
+
+// Normalized resolves SelectedNominal and SelectedContinuous keys given as
+// user-defined #TraitIDs to internal trait IDs, dropping keys that name no trait
+// of the matching type. Legacy integer answers for continuous traits in Selected
+// are moved into SelectedContinuous, so the engine only reads measurements there.
+func (o Observations) Normalized(m *Matrix) Observations {
+	if m == nil {
+		return o
+	}
+	nominalKeys := traitKeys(m, "nominal_parent")
+	continuousKeys := traitKeys(m, "continuous")
+
+	out := o
+	out.Selected = make(map[string]int, len(o.Selected))
+	out.SelectedContinuous = make(map[string]ContinuousObservation, len(o.SelectedContinuous))
+	for k, v := range o.SelectedContinuous {
+		if id, ok := continuousKeys[k]; ok {
+			out.SelectedContinuous[id] = v
+		}
+	}
+	for k, v := range o.Selected {
+		id, isContinuous := continuousKeys[k]
+		if !isContinuous {
+			out.Selected[k] = v
+			continue
+		}
+		if _, ok := out.SelectedContinuous[id]; !ok && v != 0 {
+			out.SelectedContinuous[id] = ContinuousObservation{Value: float64(v)}
+		}
+	}
+	out.SelectedNominal = make(map[string]string, len(o.SelectedNominal))
+	for k, v := range o.SelectedNominal {
+		if id, ok := nominalKeys[k]; ok && strings.TrimSpace(v) != "" {
+			out.SelectedNominal[id] = v
+		}
+	}
+	return out
+}
+
+// traitKeys maps both the internal ID and the #TraitID of every trait of the
+// given type to its internal ID.
+func traitKeys(m *Matrix, typ string) map[string]string {
+	out := make(map[string]string)
+	for _, t := range m.Traits {
+		if t.Type != typ {
+			continue
+		}
+		if t.TraitID != "" {
+			out[t.TraitID] = t.ID
+		}
+		out[t.ID] = t.ID
+	}
+	return out
+}
+
+// Resolve prepares observations for evaluation. Traits marked as unobservable
+// override any answer still recorded for them, and, when applyDependencies is
+// set, dependent traits ruled out by their parent's answer are inapplicable to
+// the specimen, so answers left behind for them no longer count. The dropped
+// trait IDs are returned alongside.
+func (o Observations) Resolve(m *Matrix, applyDependencies bool) (active Observations, unobservable, inactive map[string]bool) {
+	unobservable = o.Unobservable(m)
+	active = o.Normalized(m).withoutTraits(unobservable)
+	if applyDependencies {
+		inactive = active.InactiveDependents(m)
+	}
+	return active.withoutTraits(inactive), unobservable, inactive
+}
+
+// withoutTraits returns a copy of the observations with the given traits removed
+// from every answer map, so stale answers cannot outlive an NA mark.
+func (o Observations) withoutTraits(skip map[string]bool) Observations {
+	if len(skip) == 0 {
+		return o
+	}
+	out := o
+	out.Selected = make(map[string]int, len(o.Selected))
+	for k, v := range o.Selected {
+		if !skip[k] {
+			out.Selected[k] = v
+		}
+	}
+	out.SelectedMulti = make(map[string][]string, len(o.SelectedMulti))
+	for k, v := range o.SelectedMulti {
+		if !skip[k] {
+			out.SelectedMulti[k] = v
+		}
+	}
+	out.SelectedNominal = make(map[string]string, len(o.SelectedNominal))
+	for k, v := range o.SelectedNominal {
+		if !skip[k] {
+			out.SelectedNominal[k] = v
+		}
+	}
+	out.SelectedContinuous = make(map[string]ContinuousObservation, len(o.SelectedContinuous))
+	for k, v := range o.SelectedContinuous {
+		if !skip[k] {
+			out.SelectedContinuous[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+// backend/engine/observations_test.go
+package engine
+
+import "testing"
+
+// The app records NA as a 0 answer; it must rule the trait out like SelectedNA.
+func TestNAAnswerIsUnobservable(t *testing.T) {
+	m := darknessMatrix()
+	m.Traits = append(m.Traits, Trait{ID: "t5", NameEN: "Hind wing present", GroupEN: "Wings", Type: "binary"})
+	for i := range m.Taxa {
+		m.Taxa[i].Traits["t5"] = Ternary(1 - 2*(i%2))
+	}
+
+	un := Observations{Selected: map[string]int{"t3": 0, "t5": 0}}.Unobservable(m)
+	for _, id := range []string{"t1", "t2", "t3", "t4", "t5"} {
+		if !un[id] {
+			t.Errorf("%s not marked unobservable: %v", id, un)
+		}
+	}
+
+	opt := testOptions()
+	opt.WantInfoGain = true
+	res, err := ApplyFiltersAlgoOpt(m, Observations{Selected: map[string]int{"t5": 0}}, "lenient", "bayes", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range res.Suggestions {
+		if s.TraitId == "t5" {
+			t.Errorf("NA trait t5 is still recommended: %+v", s)
+		}
+	}
+	if len(res.Suggestions) == 0 {
+		t.Error("want the darkness trait still recommended")
+	}
+}
+
+// Marking a group rules out every trait in it, matched on either language.
+func TestUnobservableGroups(t *testing.T) {
+	m := darknessMatrix()
+	m.Traits = append(m.Traits, Trait{ID: "t5", NameEN: "Hind wing present", GroupEN: "Wings", GroupJP: "翅", Type: "binary"})
+	un := Observations{SelectedNAGroups: []string{" 翅 "}}.Unobservable(m)
+	if !un["t5"] || un["t1"] {
+		t.Errorf("group mark gave %v, want only t5", un)
+	}
+}
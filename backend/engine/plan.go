@@ -0,0 +1,205 @@
+// backend/engine/plan.go
+package engine
+
+import (
+	"math"
+	"sort"
+)
+
+// StrategyPlan is the RecommendationStrategy that looks several observations
+// ahead and ranks traits by the expected total #Difficulty needed to reach the
+// target confidence, instead of by one-step information gain.
+const StrategyPlan = "plan"
+
+const (
+	defaultPlanDepth = 2 // Observations searched explicitly, the recommended one included
+	maxPlanDepth     = 3
+	planBeam         = 4  // Traits expanded at each node below the root
+	planRootWidth    = 8  // Root traits searched in full; the rest get the leaf estimate
+	planPoolSize     = 16 // Traits considered below the root, best root IG per difficulty first
+)
+
+// planner runs a bounded-depth expectimax over the remaining traits. Below the
+// root only the planBeam traits with the best IG per difficulty are expanded,
+// and the search is cut off with an estimate of the remaining cost.
+type planner struct {
+	models     []answerModel
+	pool       []int // Traits candidates are drawn from; nil means all
+	difficulty []float64
+	target     float64 // Probability the top taxon must reach
+	residual   float64 // Entropy (bits) still allowed once the target is reached
+}
+
+// planCandidate is an unused trait with its expected IG at some posterior.
+type planCandidate struct {
+	i    int
+	ig   float64
+	rate float64 // IG per difficulty
+}
+
+// planSuggestions replaces the scores of out (one per model, in the same order)
+// with the plan strategy's. The planRootWidth traits with the best IG per
+// difficulty are searched in full; the others are costed with the leaf estimate
+// of what they leave, which keeps the work bounded on large keys.
+// ExpectedCost/ExpectedSteps give the cost and length of the path each starts.
+func planSuggestions(post []float64, models []answerModel, out []TraitSuggestion, opt AlgoOptions) {
+	target := opt.CredibleLevel
+	if target <= 0 || target > 1 {
+		target = defaultCredibleLevel
+	}
+	if maxOf(post) >= target {
+		return // Already confident; the one-step scores stand
+	}
+	depth := opt.PlanDepth
+	if depth <= 0 {
+		depth = defaultPlanDepth
+	}
+	if depth > maxPlanDepth {
+		depth = maxPlanDepth
+	}
+
+	p := &planner{
+		models:     models,
+		difficulty: make([]float64, len(models)),
+		target:     target,
+		residual:   shannon([]float64{target, 1 - target}),
+	}
+	for i := range models {
+		p.difficulty[i] = out[i].Difficulty
+	}
+
+	used := make([]bool, len(models))
+	roots := p.candidates(post, used)
+	for r := 0; r < len(roots) && r < planPoolSize; r++ {
+		p.pool = append(p.pool, roots[r].i)
+	}
+	searched := make([]bool, len(models))
+	for r := 0; r < len(roots) && r < planRootWidth; r++ {
+		searched[roots[r].i] = true
+	}
+	for i := range out {
+		var cost, steps float64
+		if searched[i] {
+			cost, steps = p.expand(i, post, used, depth)
+		} else {
+			cost, steps = p.estimate(i, post, roots)
+		}
+		out[i].ExpectedCost = cost
+		out[i].ExpectedSteps = steps
+		out[i].Score = 1 / (1 + cost)
+		if opt.UsePragmaticScore {
+			out[i].Score *= 1 - out[i].Risk
+		}
+	}
+}
+
+// expand returns the expected cost and number of observations needed to reach
+// the target from post when trait i is observed next, searching depth-1 further
+// observations after it.
+func (p *planner) expand(i int, post []float64, used []bool, depth int) (cost, steps float64) {
+	py, postY := p.models[i].outcomes(post)
+	used[i] = true
+	defer func() { used[i] = false }()
+
+	cost, steps = p.difficulty[i], 1
+	for s, ps := range py {
+		if ps <= 0 {
+			continue
+		}
+		c, n := p.value(postY[s], used, depth-1)
+		cost += ps * c
+		steps += ps * n
+	}
+	return cost, steps
+}
+
+// value is the expectimax value of post: the cheapest expected continuation
+// over the beam, or the leaf estimate once the depth is used up. It is zero
+// once the target is reached or no remaining trait is informative.
+func (p *planner) value(post []float64, used []bool, depth int) (cost, steps float64) {
+	if maxOf(post) >= p.target {
+		return 0, 0
+	}
+	cands := p.candidates(post, used)
+	if len(cands) == 0 {
+		return 0, 0
+	}
+	if depth <= 0 {
+		return p.leaf(post, cands)
+	}
+	if len(cands) > planBeam {
+		cands = cands[:planBeam]
+	}
+	cost = math.Inf(1)
+	for _, c := range cands {
+		if ec, n := p.expand(c.i, post, used, depth); ec < cost {
+			cost, steps = ec, n
+		}
+	}
+	return cost, steps
+}
+
+// leaf estimates the remaining cost as a fractional knapsack: the entropy above
+// the residual is bought from the candidates in order of IG per difficulty,
+// each supplying at most its current expected IG.
+func (p *planner) leaf(post []float64, cands []planCandidate) (cost, steps float64) {
+	bits := shannon(post) - p.residual
+	for _, c := range cands {
+		if bits <= 0 {
+			break
+		}
+		take := math.Min(bits, c.ig)
+		cost += take / c.rate
+		steps += take / c.ig
+		bits -= take
+	}
+	return cost, steps
+}
+
+// estimate is the cost of observing trait i first without searching further:
+// its difficulty plus the leaf estimate for the entropy it is expected to
+// leave, bought from the other root candidates.
+func (p *planner) estimate(i int, post []float64, roots []planCandidate) (cost, steps float64) {
+	hNow := shannon(post)
+	bits := hNow - p.models[i].infoGain(post, hNow) - p.residual
+	cost, steps = p.difficulty[i], 1
+	for _, c := range roots {
+		if bits <= 0 {
+			break
+		}
+		if c.i == i {
+			continue
+		}
+		take := math.Min(bits, c.ig)
+		cost += take / c.rate
+		steps += take / c.ig
+		bits -= take
+	}
+	return cost, steps
+}
+
+// candidates returns the unused informative traits at post, drawn from the pool
+// once it is set, best IG per difficulty first.
+func (p *planner) candidates(post []float64, used []bool) []planCandidate {
+	hNow := shannon(post)
+	var cands []planCandidate
+	consider := func(i int) {
+		if used[i] {
+			return
+		}
+		if ig := p.models[i].infoGain(post, hNow); ig > 1e-9 {
+			cands = append(cands, planCandidate{i, ig, ig / p.difficulty[i]})
+		}
+	}
+	if p.pool == nil {
+		for i := range p.models {
+			consider(i)
+		}
+	} else {
+		for _, i := range p.pool {
+			consider(i)
+		}
+	}
+	sort.Slice(cands, func(a, b int) bool { return cands[a].rate > cands[b].rate })
+	return cands
+}
@@ -0,0 +1,98 @@
+// backend/engine/plan_test.go
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+// planMatrix separates two taxa with three cheap but unreliable traits and one
+// dearer trait that is almost never misread.
+func planMatrix() *Matrix {
+	m := &Matrix{Traits: []Trait{
+		{ID: "w1", NameEN: "Weak 1", Type: "binary", Difficulty: 1, AlphaFP: 0.1, BetaFN: 0.1},
+		{ID: "w2", NameEN: "Weak 2", Type: "binary", Difficulty: 1, AlphaFP: 0.1, BetaFN: 0.1},
+		{ID: "w3", NameEN: "Weak 3", Type: "binary", Difficulty: 1, AlphaFP: 0.1, BetaFN: 0.1},
+		{ID: "d", NameEN: "Decisive", Type: "binary", Difficulty: 2, AlphaFP: 0.01, BetaFN: 0.01},
+	}}
+	for _, tx := range []struct {
+		id string
+		v  Ternary
+	}{{"A", Yes}, {"B", No}} {
+		traits := map[string]Ternary{}
+		for _, tr := range m.Traits {
+			traits[tr.ID] = tx.v
+		}
+		m.Taxa = append(m.Taxa, Taxon{ID: tx.id, Name: tx.id, Traits: traits})
+	}
+	return m
+}
+
+// planOptions scores conflicts by the error rates alone, so that a misread
+// weak trait only shifts the posterior instead of excluding a taxon.
+func planOptions() AlgoOptions {
+	opt := testOptions()
+	opt.ConflictPenalty = 0
+	return opt
+}
+
+func planScores(m *Matrix, opt AlgoOptions) map[string]TraitSuggestion {
+	out := make(map[string]TraitSuggestion)
+	for _, s := range SuggestTraitsBayes(m, []float64{0.5, 0.5}, 0.1, nil, nil, opt) {
+		out[s.TraitId] = s
+	}
+	return out
+}
+
+// Per unit of #Difficulty a weak trait looks better one step ahead, but one
+// weak answer cannot reach the target, so the plan prefers the decisive trait.
+func TestPlanPrefersDecisiveTrait(t *testing.T) {
+	m := planMatrix()
+	opt := planOptions()
+	opt.UsePragmaticScore = true
+
+	step := planScores(m, opt)
+	if step["w1"].Score <= step["d"].Score {
+		t.Fatalf("one-step: weak %.4f should outscore decisive %.4f per difficulty", step["w1"].Score, step["d"].Score)
+	}
+
+	opt.RecommendationStrategy = StrategyPlan
+	plan := planScores(m, opt)
+	if plan["d"].Score <= plan["w1"].Score {
+		t.Errorf("plan: decisive %.4f (cost %.3f) should outscore weak %.4f (cost %.3f)",
+			plan["d"].Score, plan["d"].ExpectedCost, plan["w1"].Score, plan["w1"].ExpectedCost)
+	}
+	if math.Abs(plan["d"].ExpectedCost-2) > 1e-9 || plan["d"].ExpectedSteps != 1 {
+		t.Errorf("plan: decisive costs %.3f in %.2f steps, want 2 in 1", plan["d"].ExpectedCost, plan["d"].ExpectedSteps)
+	}
+	if plan["w1"].ExpectedSteps <= 1 {
+		t.Errorf("plan: weak expects %.2f steps, want more than one", plan["w1"].ExpectedSteps)
+	}
+}
+
+// PlanDepth is clamped to maxPlanDepth, and 0 selects the default.
+func TestPlanDepthClamp(t *testing.T) {
+	m := planMatrix()
+	opt := planOptions()
+	opt.RecommendationStrategy = StrategyPlan
+
+	costs := func(depth int) map[string]float64 {
+		opt.PlanDepth = depth
+		out := make(map[string]float64)
+		for id, s := range planScores(m, opt) {
+			out[id] = s.ExpectedCost
+		}
+		return out
+	}
+	for _, c := range []struct{ depth, same int }{{10, maxPlanDepth}, {0, defaultPlanDepth}, {-1, defaultPlanDepth}} {
+		got, want := costs(c.depth), costs(c.same)
+		for id := range want {
+			if got[id] != want[id] {
+				t.Errorf("depth %d: %s costs %.4f, depth %d gives %.4f", c.depth, id, got[id], c.same, want[id])
+			}
+		}
+	}
+	if shallow, deep := costs(1), costs(maxPlanDepth); shallow["w1"] == deep["w1"] {
+		t.Errorf("weak trait costs %.4f at depth 1 and %d; the depth has no effect", deep["w1"], maxPlanDepth)
+	}
+}
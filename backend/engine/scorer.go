@@ -0,0 +1,200 @@
+// backend/engine/scorer.go
+package engine
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Scorer is an identification algorithm. ApplyFiltersAlgoOpt resolves the
+// request's algo name through the registry, so a new algorithm only needs to
+// implement Scorer and call RegisterScorer (typically from an init function).
+type Scorer interface {
+	// Info describes the algorithm and the AlgoOptions fields it reads.
+	Info() AlgorithmInfo
+	// Score ranks every taxon in m against the resolved observations. Post must be
+	// non-negative; it is normalised over the taxa to drive trait suggestions.
+	Score(m *Matrix, obs Observations, opt AlgoOptions) ([]TaxonScore, error)
+}
+
+// OpenSetScorer is implemented by scorers that can also score a "taxon not in
+// this key" class (AlgoOptions.UnknownPrior). unknown is that class's
+// posterior; the taxon Posts then sum to 1-unknown.
+type OpenSetScorer interface {
+	Scorer
+	ScoreOpenSet(m *Matrix, obs Observations, opt AlgoOptions) (scores []TaxonScore, unknown float64, err error)
+}
+
+// AlgorithmInfo is what the UI needs to offer an algorithm and its settings.
+type AlgorithmInfo struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	// Probabilistic is true when TaxonScore.Post is a posterior probability
+	// rather than a similarity score.
+	Probabilistic bool        `json:"probabilistic"`
+	Params        []AlgoParam `json:"params"`
+}
+
+// AlgoParam describes one tunable AlgoOptions field by its JSON name.
+type AlgoParam struct {
+	Key         string   `json:"key"`
+	Type        string   `json:"type"` // "number", "integer", "string", "boolean", "map" or "list"
+	Description string   `json:"description"`
+	Default     any      `json:"default,omitempty"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+}
+
+// DefaultAlgo is used when a request does not name an algorithm.
+const DefaultAlgo = "bayes"
+
+var (
+	scorerMu    sync.RWMutex
+	scorers     = make(map[string]Scorer)
+	scorerOrder []string
+)
+
+// RegisterScorer adds an algorithm to the registry. Names must be unique.
+func RegisterScorer(s Scorer) error {
+	name := s.Info().Name
+	if name == "" {
+		return fmt.Errorf("scorer has no name")
+	}
+	scorerMu.Lock()
+	defer scorerMu.Unlock()
+	if _, dup := scorers[name]; dup {
+		return fmt.Errorf("scorer %q is already registered", name)
+	}
+	scorers[name] = s
+	scorerOrder = append(scorerOrder, name)
+	return nil
+}
+
+// LookupScorer returns the registered algorithm with the given name;
+// an empty name selects DefaultAlgo.
+func LookupScorer(name string) (Scorer, error) {
+	if name == "" {
+		name = DefaultAlgo
+	}
+	scorerMu.RLock()
+	defer scorerMu.RUnlock()
+	s, ok := scorers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown algorithm %q", name)
+	}
+	return s, nil
+}
+
+// Algorithms lists the registered algorithms in registration order. Options
+// that apply to every algorithm (modes, observation confidence) are appended
+// to each parameter list.
+func Algorithms() []AlgorithmInfo {
+	scorerMu.RLock()
+	defer scorerMu.RUnlock()
+	out := make([]AlgorithmInfo, 0, len(scorerOrder))
+	for _, name := range scorerOrder {
+		info := scorers[name].Info()
+		info.Params = append(append([]AlgoParam(nil), info.Params...), commonParams...)
+		out = append(out, info)
+	}
+	return out
+}
+
+func bound(v float64) *float64 { return &v }
+
+func numberParam(key, desc string, def, min, max float64) AlgoParam {
+	return AlgoParam{Key: key, Type: "number", Description: desc, Default: def, Min: bound(min), Max: bound(max)}
+}
+
+var commonParams = []AlgoParam{
+	{Key: "maxConflicts", Type: "integer", Description: "Conflicts a taxon may have before the \"tolerant\" mode drops it", Default: 0, Min: bound(0)},
+	numberParam("rankThreshold", "Probability a taxonomic group needs to be reported as confident", defaultRankThreshold, 0.5, 1),
+	numberParam("credibleLevel", "Probability mass the credible set of taxa must cover", defaultCredibleLevel, 0.5, 1),
+	numberParam("stopRatio", "Top-to-second probability ratio needed before stopping", defaultStopRatio, 1, 1000),
+	numberParam("minInfoGain", "Expected information gain (bits) below which no trait is worth asking", defaultMinInfoGain, 0, 1),
+	{Key: "confidence", Type: "map", Description: "Per-trait confidence in [0,1] for the recorded answers; 0 ignores the answer"},
+}
+
+var categoricalParams = []AlgoParam{
+	{Key: "categoricalAlgo", Type: "string", Description: "How multi-state answers are compared", Default: "binary", Choices: []string{"binary", "jaccard"}},
+	numberParam("jaccardThreshold", "Jaccard similarity counted as a match", 0.5, 0, 1),
+}
+
+type bayesScorer struct{}
+
+func (bayesScorer) Info() AlgorithmInfo {
+	params := []AlgoParam{
+		numberParam("defaultAlphaFP", "False-positive rate for traits without #AlphaFP", 0.03, 0, 0.5),
+		numberParam("defaultBetaFN", "False-negative rate for traits without #BetaFN", 0.07, 0, 0.5),
+		numberParam("gammaNAPenalty", "Likelihood factor for cells with no data", 0.95, 0, 1),
+		numberParam("kappa", "Softmax sharpness of the posterior", 1, 0, 5),
+		numberParam("lambda", "Likelihood tempering exponent", 1, 0, 3),
+		numberParam("priorStrength", "Weight in answers of the prior on per-trait error rates, centred on #AlphaFP/#BetaFN", 2, 0, 100),
+		numberParam("conflictPenalty", "Extra penalty for a contradicting answer", 0, 0, 1),
+		numberParam("toleranceFactor", "Tolerance of continuous measurements outside the range", 0, 0, 1),
+		{Key: "continuousAlgo", Type: "string", Description: "Likelihood model for continuous traits", Default: ContinuousRange, Choices: []string{ContinuousRange, ContinuousGaussian, ContinuousTruncatedNormal}},
+		numberParam("rangeCoverage", "Quantile interval a min-max range stands for", defaultRangeCoverage, 0.5, 0.999),
+		{Key: "alphaFP", Type: "map", Description: "Per-trait false-positive rates overriding the matrix"},
+		{Key: "betaFN", Type: "map", Description: "Per-trait false-negative rates overriding the matrix"},
+		{Key: "priors", Type: "map", Description: "Per-taxon prior weights overriding #Prior"},
+		numberParam("unknownPrior", "Prior of a \"taxon not in this key\" class (0 = off)", 0, 0, 0.5),
+		{Key: "confirmed", Type: "list", Description: "Confirmed identifications that update per-trait error rates"},
+	}
+	return AlgorithmInfo{
+		Name:          "bayes",
+		Label:         "Bayes",
+		Description:   "Posterior probability of each taxon under per-trait error rates.",
+		Probabilistic: true,
+		Params:        append(params, categoricalParams...),
+	}
+}
+
+func (bayesScorer) Score(m *Matrix, obs Observations, opt AlgoOptions) ([]TaxonScore, error) {
+	opt.UnknownPrior = 0
+	_, scores, _, err := evaluateBayes(m, obs, opt)
+	return scores, err
+}
+
+func (bayesScorer) ScoreOpenSet(m *Matrix, obs Observations, opt AlgoOptions) ([]TaxonScore, float64, error) {
+	_, scores, unknown, err := evaluateBayes(m, obs, opt)
+	return scores, unknown, err
+}
+
+type heuristicScorer struct{}
+
+func (heuristicScorer) Info() AlgorithmInfo {
+	return AlgorithmInfo{
+		Name:        "heuristic",
+		Label:       "Heuristic",
+		Description: "Share of answered traits each taxon matches.",
+		Params:      append([]AlgoParam{numberParam("gammaNAPenalty", "Credit factor for cells with no data", 0.95, 0, 1)}, categoricalParams...),
+	}
+}
+
+func (heuristicScorer) Score(m *Matrix, obs Observations, opt AlgoOptions) ([]TaxonScore, error) {
+	return evaluateHeuristic(m, obs, opt)
+}
+
+type gowerScorer struct{}
+
+func (gowerScorer) Info() AlgorithmInfo {
+	return AlgorithmInfo{
+		Name:        AlgoGower,
+		Label:       "Gower (distance)",
+		Description: "Nearest taxa by Gower dissimilarity; no error parameters.",
+	}
+}
+
+func (gowerScorer) Score(m *Matrix, obs Observations, opt AlgoOptions) ([]TaxonScore, error) {
+	return evaluateGower(m, obs, opt)
+}
+
+func init() {
+	for _, s := range []Scorer{bayesScorer{}, heuristicScorer{}, gowerScorer{}} {
+		if err := RegisterScorer(s); err != nil {
+			panic(err)
+		}
+	}
+}
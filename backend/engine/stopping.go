@@ -0,0 +1,150 @@
+// backend/engine/stopping.go
+package engine
+
+import (
+	"math"
+	"sort"
+)
+
+// Defaults for the stopping criterion, used when the AlgoOptions fields are unset.
+const (
+	defaultCredibleLevel = 0.95 // Posterior mass the credible set must cover
+	defaultStopRatio     = 10.0 // Top-to-second probability ratio needed to stop
+	defaultMinInfoGain   = 0.01 // Expected IG (bits) below which no trait is worth asking
+)
+
+// Confidence levels of a StopVerdict, from the report's confidence wording.
+const (
+	ConfidenceAbsolute  = "absolute"  // Only one candidate remains
+	ConfidenceVeryHigh  = "very_high" // Every other candidate has probability zero
+	ConfidenceHigh      = "high"      // Credible set of one, and the top/second ratio is reached
+	ConfidenceContested = "contested" // Top candidates are too close to call
+	ConfidenceNA        = "na"        // No candidates
+)
+
+// CredibleSet is the smallest set of taxa whose summed probability reaches Level.
+type CredibleSet struct {
+	Level    float64  `json:"level"`
+	TaxonIDs []string `json:"taxonIds"` // In descending order of probability
+	Mass     float64  `json:"mass"`
+	// Reached is false when every taxon together stays below Level, e.g. because
+	// the "taxon not in this key" class holds the rest of the mass.
+	Reached bool `json:"reached"`
+}
+
+// StopVerdict tells whether the identification can stop or needs more traits.
+type StopVerdict struct {
+	Stop       bool    `json:"stop"`
+	Confidence string  `json:"confidence"`
+	Reason     string  `json:"reason"`
+	SetSize    int     `json:"setSize"`
+	Ratio      float64 `json:"ratio"`       // Top over second probability; 0 when the second is zero
+	BestIG     float64 `json:"bestIG"`      // Highest expected IG among the suggestions
+	HasIG      bool    `json:"hasIG"`       // False when suggestions were not computed
+	Level      float64 `json:"level"`       // Credible level used
+	StopRatio  float64 `json:"stopRatio"`   // Ratio threshold used
+	MinIG      float64 `json:"minInfoGain"` // IG threshold used
+}
+
+// Reasons reported with a StopVerdict.
+const (
+	StopSingleCandidate = "single_candidate" // Nothing left to separate
+	StopCredibleSet     = "credible_set"     // One taxon covers the credible level and leads clearly
+	StopNoInformative   = "no_informative"   // No remaining trait is expected to help
+	StopContinue        = "continue"         // More observations are worth making
+	StopNoCandidates    = "no_candidates"
+)
+
+// ComputeCredibleSet returns the smallest set of taxa whose probability mass
+// reaches level. mass need not sum to one; it is taken as is, so mass held by
+// an open-set class counts against the level.
+func ComputeCredibleSet(mass map[string]float64, level float64) CredibleSet {
+	if level <= 0 || level > 1 {
+		level = defaultCredibleLevel
+	}
+	ids := make([]string, 0, len(mass))
+	for id, p := range mass {
+		if p > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if mass[ids[i]] == mass[ids[j]] {
+			return ids[i] < ids[j]
+		}
+		return mass[ids[i]] > mass[ids[j]]
+	})
+
+	cs := CredibleSet{Level: level, TaxonIDs: []string{}}
+	for _, id := range ids {
+		cs.TaxonIDs = append(cs.TaxonIDs, id)
+		cs.Mass += mass[id]
+		if cs.Mass >= level-1e-12 {
+			cs.Reached = true
+			break
+		}
+	}
+	return cs
+}
+
+// DecideStop combines the credible set, the top-to-second ratio of the ranked
+// probabilities and the best remaining expected IG into a stop/continue verdict.
+// ranked is the taxon probability in descending order; sugg may be nil when
+// suggestions were not requested, in which case the IG criterion is skipped.
+func DecideStop(ranked []float64, cs CredibleSet, sugg []TraitSuggestion, opt AlgoOptions) StopVerdict {
+	v := StopVerdict{
+		Level:     cs.Level,
+		StopRatio: opt.StopRatio,
+		MinIG:     opt.MinInfoGain,
+		SetSize:   len(cs.TaxonIDs),
+	}
+	if v.StopRatio <= 1 {
+		v.StopRatio = defaultStopRatio
+	}
+	if v.MinIG <= 0 {
+		v.MinIG = defaultMinInfoGain
+	}
+	if sugg != nil {
+		v.HasIG = true
+		for _, s := range sugg {
+			v.BestIG = math.Max(v.BestIG, s.IG)
+		}
+	}
+
+	var top, second float64
+	if len(ranked) > 0 {
+		top = ranked[0]
+	}
+	if len(ranked) > 1 {
+		second = ranked[1]
+	}
+	if top > 0 && second > 0 {
+		v.Ratio = top / second
+	}
+
+	switch {
+	case len(ranked) == 0 || top <= 0:
+		v.Confidence, v.Reason = ConfidenceNA, StopNoCandidates
+		return v
+	case len(ranked) == 1:
+		v.Confidence, v.Reason, v.Stop = ConfidenceAbsolute, StopSingleCandidate, true
+		return v
+	case second <= 0:
+		v.Confidence = ConfidenceVeryHigh
+	case cs.Reached && v.SetSize == 1 && v.Ratio >= v.StopRatio:
+		v.Confidence = ConfidenceHigh
+	default:
+		v.Confidence = ConfidenceContested
+	}
+
+	switch {
+	case v.Confidence != ConfidenceContested && cs.Reached && v.SetSize == 1:
+		v.Stop, v.Reason = true, StopCredibleSet
+	case v.HasIG && v.BestIG < v.MinIG:
+		// Still undecided, but nothing left to ask would change that.
+		v.Stop, v.Reason = true, StopNoInformative
+	default:
+		v.Reason = StopContinue
+	}
+	return v
+}
@@ -0,0 +1,64 @@
+// backend/engine/stopping_test.go
+package engine
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// The credible set takes mass as given, so probability held by the "not in
+// this key" class keeps it from reaching the level.
+func TestComputeCredibleSet(t *testing.T) {
+	cs := ComputeCredibleSet(map[string]float64{"A": 0.6, "B": 0.3, "C": 0.1}, 0.85)
+	if !cs.Reached || !reflect.DeepEqual(cs.TaxonIDs, []string{"A", "B"}) || math.Abs(cs.Mass-0.9) > 1e-9 {
+		t.Errorf("closed set: got %+v, want A, B reaching 0.9", cs)
+	}
+
+	cs = ComputeCredibleSet(map[string]float64{"A": 0.5, "B": 0.3}, 0.95)
+	if cs.Reached || !reflect.DeepEqual(cs.TaxonIDs, []string{"A", "B"}) || math.Abs(cs.Mass-0.8) > 1e-9 {
+		t.Errorf("0.2 unknown mass: got %+v, want A, B not reaching 0.95", cs)
+	}
+
+	if cs := ComputeCredibleSet(map[string]float64{"A": 1}, 0); cs.Level != defaultCredibleLevel {
+		t.Errorf("level 0 gave %.2f, want the default %.2f", cs.Level, defaultCredibleLevel)
+	}
+}
+
+// Each reason DecideStop can give, from the ranked probabilities and the
+// best expected IG among the suggestions.
+func TestDecideStopReasons(t *testing.T) {
+	informative := []TraitSuggestion{{TraitId: "t1", IG: 0.5}}
+	useless := []TraitSuggestion{{TraitId: "t1", IG: 0.001}}
+
+	tests := []struct {
+		name       string
+		mass       map[string]float64
+		sugg       []TraitSuggestion
+		stop       bool
+		reason     string
+		confidence string
+	}{
+		{"empty", map[string]float64{}, informative, false, StopNoCandidates, ConfidenceNA},
+		{"one left", map[string]float64{"A": 1}, informative, true, StopSingleCandidate, ConfidenceAbsolute},
+		{"others zero", map[string]float64{"A": 1, "B": 0}, informative, true, StopCredibleSet, ConfidenceVeryHigh},
+		{"clear lead", map[string]float64{"A": 0.97, "B": 0.03}, informative, true, StopCredibleSet, ConfidenceHigh},
+		{"covers level, ratio too low", map[string]float64{"A": 0.95, "B": 0.1}, informative, false, StopContinue, ConfidenceContested},
+		{"close, worth asking", map[string]float64{"A": 0.5, "B": 0.5}, informative, false, StopContinue, ConfidenceContested},
+		{"close, nothing to ask", map[string]float64{"A": 0.5, "B": 0.5}, useless, true, StopNoInformative, ConfidenceContested},
+		{"close, no suggestions", map[string]float64{"A": 0.5, "B": 0.5}, nil, false, StopContinue, ConfidenceContested},
+	}
+	for _, tt := range tests {
+		cs := ComputeCredibleSet(tt.mass, 0.95)
+		var ranked []float64
+		for _, id := range []string{"A", "B", "C"} {
+			if p, ok := tt.mass[id]; ok {
+				ranked = append(ranked, p)
+			}
+		}
+		v := DecideStop(ranked, cs, tt.sugg, AlgoOptions{})
+		if v.Stop != tt.stop || v.Reason != tt.reason || v.Confidence != tt.confidence {
+			t.Errorf("%s: got stop %v, %s, %s; want %v, %s, %s", tt.name, v.Stop, v.Reason, v.Confidence, tt.stop, tt.reason, tt.confidence)
+		}
+	}
+}
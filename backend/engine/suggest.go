@@ -2,22 +2,27 @@
 package engine
 
 import (
+	"math"
 	"sort"
+	"strconv"
 	"strings"
 )
 
-// ... (computeMatchStats, stateDef, getTraitMetaMap, buildStateDefs, compatibleBinary remain the same)
 func computeMatchStats(obs map[string]Ternary, tx *Taxon) (matches, support, conflicts int) {
 	for tid, o := range obs {
 		if o == NA {
 			continue
 		}
 		support++
+		if tx.Inapplicable[tid] {
+			conflicts++
+			continue
+		}
 		v, ok := tx.Traits[tid]
 		if !ok || v == NA {
 			continue
 		}
-		if o == v {
+		if o == v || v == Variable {
 			matches++
 		} else {
 			conflicts++
@@ -27,12 +32,17 @@ func computeMatchStats(obs map[string]Ternary, tx *Taxon) (matches, support, con
 }
 
 type stateDef struct {
-	yesNo    bool
-	childIDs []string
-	labels   []string
-	traitID  string
-	name     string
-	group    string
+	yesNo      bool
+	ordinal    bool
+	continuous bool      // Answers are the bins between edges
+	multi      bool      // categorical_multi: answers are the states in labels
+	edges      []float64 // Bin edges of a continuous trait, ascending
+	span       float64   // Overall range of a continuous trait, as given to the Bayes core
+	childIDs   []string
+	labels     []string
+	traitID    string
+	name       string
+	group      string
 }
 
 func getTraitMetaMap(traits []Trait) map[string]Trait {
@@ -65,14 +75,44 @@ func buildStateDefs(m *Matrix) []stateDef {
 		}
 		kids := append([]Trait{}, childrenByParent[t.TraitID]...)
 		kids = append(kids, childrenByParent[t.NameEN]...)
-		if len(kids) == 0 {
+		switch {
+		case t.Type == "continuous":
+			edges := continuousBinEdges(m, t.ID)
+			if len(edges) < 3 {
+				continue // A single bin cannot tell taxa apart
+			}
+			labels := make([]string, len(edges)-1)
+			for b := range labels {
+				labels[b] = formatBin(edges[b], edges[b+1])
+			}
+			out = append(out, stateDef{
+				continuous: true,
+				edges:      edges,
+				span:       continuousSpan(t, 0),
+				labels:     labels,
+				traitID:    t.ID,
+				name:       t.NameEN,
+				group:      t.GroupEN,
+			})
+		case t.Type == "categorical_multi":
+			if len(t.States) < 2 {
+				continue
+			}
+			out = append(out, stateDef{
+				multi:   true,
+				labels:  append([]string{}, t.States...),
+				traitID: t.ID,
+				name:    t.NameEN,
+				group:   t.GroupEN,
+			})
+		case len(kids) == 0:
 			out = append(out, stateDef{
 				yesNo:   true,
 				traitID: t.ID,
 				name:    t.NameEN,
 				group:   t.GroupEN,
 			})
-		} else {
+		default:
 			ids := make([]string, 0, len(kids))
 			lb := make([]string, 0, len(kids))
 			for _, c := range kids {
@@ -86,6 +126,7 @@ func buildStateDefs(m *Matrix) []stateDef {
 				}
 			}
 			out = append(out, stateDef{
+				ordinal:  t.Ordinal,
 				traitID:  t.ID,
 				name:     t.NameEN,
 				group:    t.GroupEN,
@@ -97,14 +138,153 @@ func buildStateDefs(m *Matrix) []stateDef {
 	return out
 }
 
-func compatibleBinary(val Ternary, wantYes bool) bool {
-	if wantYes {
-		return val == Yes
+// truthStateIndices returns the indices of the def's states the taxon is recorded with.
+func (d stateDef) truthStateIndices(tx *Taxon) []int {
+	var out []int
+	for s, cid := range d.childIDs {
+		if tx.Traits[cid] == Yes {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// bayesTruth returns the taxon's record for d in the form the Bayes core scores,
+// as the getTruth of evaluateBayes would build it.
+func (d stateDef) bayesTruth(tx *Taxon) BayesTruth {
+	if tx.Inapplicable[d.traitID] {
+		return BayesTruth{Inapplicable: true}
+	}
+	switch {
+	case d.continuous:
+		if v, ok := tx.ContinuousTraits[d.traitID]; ok {
+			return BayesTruth{Kind: BayesTraitContinuous, Min: v.Min, Max: v.Max, Mean: v.Mean, SD: v.SD}
+		}
+		return BayesTruth{Kind: BayesTraitContinuous, Unknown: true}
+	case d.multi:
+		if v := tx.CategoricalTraits[d.traitID]; len(v) > 0 {
+			return BayesTruth{Kind: BayesTraitCategoricalMulti, StatesMulti: v}
+		}
+		return BayesTruth{Kind: BayesTraitCategoricalMulti, Unknown: true}
+	case d.yesNo:
+		switch v := tx.Traits[d.traitID]; v {
+		case Yes, No:
+			return BayesTruth{Kind: BayesTraitBinary, K: 2, States: []int{int(v)}}
+		case Variable:
+			return BayesTruth{Kind: BayesTraitBinary, K: 2, States: []int{1, -1}, Weights: []float64{0.5, 0.5}}
+		}
+		return BayesTruth{Kind: BayesTraitBinary, K: 2, Unknown: true}
+	}
+	kind := BayesTraitNominal
+	if d.ordinal {
+		kind = BayesTraitOrdinal
+	}
+	if states := d.truthStateIndices(tx); len(states) > 0 {
+		return BayesTruth{Kind: kind, K: len(d.childIDs), States: states}
+	}
+	return BayesTruth{Kind: kind, K: len(d.childIDs), Unknown: true}
+}
+
+// answers returns the number of possible answers to d.
+func (d stateDef) answers() int {
+	switch {
+	case d.yesNo:
+		return 2
+	case d.continuous, d.multi:
+		return len(d.labels)
 	}
-	return val == No
+	return len(d.childIDs)
 }
 
-func SuggestTraitsBayes(m *Matrix, post []float64, tau float64, selected map[string]int, opt AlgoOptions) []TraitSuggestion {
+// answerObs returns answer s to d as the observation the Bayes core will score.
+// A continuous answer carries no value; answerLik fills it in across the bin.
+func (d stateDef) answerObs(s int, confidence float64) BayesObservation {
+	switch {
+	case d.continuous:
+		return BayesObservation{Kind: BayesTraitContinuous, Span: d.span, Confidence: confidence}
+	case d.multi:
+		return BayesObservation{Kind: BayesTraitCategoricalMulti, StatesMulti: []string{d.labels[s]}, Confidence: confidence}
+	case d.yesNo:
+		state := 1
+		if s == 1 {
+			state = -1
+		}
+		return BayesObservation{Kind: BayesTraitBinary, K: 2, State: state, Confidence: confidence}
+	}
+	kind := BayesTraitNominal
+	if d.ordinal {
+		kind = BayesTraitOrdinal
+	}
+	return BayesObservation{Kind: kind, K: len(d.childIDs), State: s, Confidence: confidence}
+}
+
+// predictive returns P(answer s | taxon) for every answer to d. Observers err at
+// the trait's alpha/beta rates; taxa with unknown truth are uniform, and taxa the
+// trait does not apply to expect no answer at all.
+func (d stateDef) predictive(truth BayesTruth, alpha, beta float64, opt AlgoOptions) []float64 {
+	k := d.answers()
+	pred := make([]float64, k)
+	switch {
+	case truth.Inapplicable:
+		return pred
+	case truth.Unknown:
+		for s := range pred {
+			pred[s] = 1.0 / float64(k)
+		}
+		return pred
+	case d.yesNo:
+		pYes := 1.0
+		if len(truth.States) > 1 {
+			pYes = truthPYes(truth)
+		} else if truth.States[0] != 1 {
+			pYes = 0
+		}
+		pred[0] = binaryMixtureProb(1, pYes, alpha, beta)
+		pred[1] = binaryMixtureProb(-1, pYes, alpha, beta)
+		return pred
+	case d.continuous:
+		v := ContinuousValue{Min: truth.Min, Max: truth.Max, Mean: truth.Mean, SD: truth.SD}
+		for s, w := range continuousBinMass(d.edges, v, opt) {
+			pred[s] = (1-alpha)*w + alpha/float64(k)
+		}
+		return pred
+	case d.multi:
+		var states []int
+		for s, label := range d.labels {
+			for _, st := range truth.StatesMulti {
+				if st == label {
+					states = append(states, s)
+					break
+				}
+			}
+		}
+		for s := range pred {
+			pred[s] = alpha / float64(k)
+		}
+		for _, s := range states {
+			pred[s] += (1 - alpha) / float64(len(states))
+		}
+		if len(states) == 0 {
+			for s := range pred {
+				pred[s] = 1.0 / float64(k) // None of the taxon's states is in the vocabulary
+			}
+		}
+		return pred
+	}
+	for s := range pred {
+		for _, t := range truth.States {
+			if d.ordinal {
+				pred[s] += ordinalStateProb(s, t, k, alpha)
+			} else {
+				pred[s] += nominalStateProb(s, t, k, alpha)
+			}
+		}
+		pred[s] /= float64(len(truth.States))
+	}
+	return pred
+}
+
+func SuggestTraitsBayes(m *Matrix, post []float64, tau float64, selected map[string]int, excluded map[string]bool, opt AlgoOptions) []TraitSuggestion {
 	if len(m.Taxa) == 0 || len(m.Traits) == 0 || len(post) != len(m.Taxa) {
 		return nil
 	}
@@ -114,14 +294,21 @@ func SuggestTraitsBayes(m *Matrix, post []float64, tau float64, selected map[str
 
 	defs := buildStateDefs(m)
 	traitMeta := getTraitMetaMap(m.Traits)
+	params := newBayesEvalParams(m, opt)
 
 	filtered := make([]stateDef, 0, len(defs))
 	for _, d := range defs {
+		// Traits that are unobservable or inapplicable on this specimen are never worth recommending.
+		if excluded[d.traitID] {
+			continue
+		}
 		skip := false
 		if d.yesNo {
 			if v, ok := selected[d.traitID]; ok && v != 0 {
 				skip = true
 			}
+		} else if selected[d.traitID] != 0 {
+			skip = true // Answered as a single state
 		} else {
 			for _, cid := range d.childIDs {
 				if selected[cid] == 1 {
@@ -136,36 +323,14 @@ func SuggestTraitsBayes(m *Matrix, post []float64, tau float64, selected map[str
 	}
 
 	out := make([]TraitSuggestion, 0, len(filtered))
-	for _, d := range filtered {
-		var py []float64
-		var labels []string
-
+	models := make([]answerModel, len(filtered))
+	for fi, d := range filtered {
+		models[fi] = buildAnswerModel(m, d, params, opt)
+		py, postY := models[fi].outcomes(post)
+		labels := d.labels
 		if d.yesNo {
-			sumY, sumN := 0.0, 0.0
-			for i, tx := range m.Taxa {
-				switch tx.Traits[d.traitID] {
-				case Yes:
-					sumY += post[i]
-				case No:
-					sumN += post[i]
-				}
-			}
-			py = []float64{sumY, sumN}
 			labels = []string{"Yes", "No"}
-		} else {
-			py = make([]float64, len(d.childIDs))
-			labels = append([]string{}, d.labels...)
-			for s, cid := range d.childIDs {
-				sum := 0.0
-				for i, tx := range m.Taxa {
-					if tx.Traits[cid] == Yes {
-						sum += post[i]
-					}
-				}
-				py[s] = sum
-			}
 		}
-		normalize(py)
 
 		gini := 1.0
 		for _, v := range py {
@@ -178,29 +343,7 @@ func SuggestTraitsBayes(m *Matrix, post []float64, tau float64, selected map[str
 		maxStateIG := -1.0 // NEW: To track the highest possible IG from any state
 
 		for s := range py {
-			postY := make([]float64, len(post))
-			if d.yesNo {
-				wantYes := (s == 0)
-				for i, tx := range m.Taxa {
-					if compatibleBinary(tx.Traits[d.traitID], wantYes) {
-						postY[i] = post[i]
-					} else {
-						postY[i] = 0
-					}
-				}
-			} else {
-				cid := d.childIDs[s]
-				for i, tx := range m.Taxa {
-					if tx.Traits[cid] == Yes {
-						postY[i] = post[i]
-					} else {
-						postY[i] = 0
-					}
-				}
-			}
-			normalize(postY)
-
-			stateH := shannon(postY)
+			stateH := shannon(postY[s])
 			stateIG := Hnow - stateH
 			if stateIG > maxStateIG {
 				maxStateIG = stateIG
@@ -208,31 +351,13 @@ func SuggestTraitsBayes(m *Matrix, post []float64, tau float64, selected map[str
 
 			expH += py[s] * stateH
 			if cNow > 0 {
-				cY := countAbove(postY, tau)
+				cY := countAbove(postY[s], tau)
 				expRed += py[s] * (1.0 - float64(cY)/float64(cNow))
 			}
 		}
 		ig := Hnow - expH
 
-		meta, ok := traitMeta[d.traitID]
-		if !ok {
-			meta, ok = traitMeta[d.name]
-		}
-		difficulty := 1.0
-		risk := 0.0
-		if ok {
-			difficulty = meta.Difficulty
-			if difficulty <= 0 {
-				difficulty = 1.0
-			}
-			risk = meta.Risk
-			if risk < 0 {
-				risk = 0
-			}
-			if risk > 1 {
-				risk = 1
-			}
-		}
+		difficulty, risk := traitCost(traitMeta, d)
 
 		var score float64
 		baseScore := ig // Default to expected IG
@@ -266,6 +391,10 @@ func SuggestTraitsBayes(m *Matrix, post []float64, tau float64, selected map[str
 		})
 	}
 
+	if opt.RecommendationStrategy == StrategyPlan {
+		planSuggestions(post, models, out, opt)
+	}
+
 	sort.Slice(out, func(i, j int) bool {
 		if out[i].Score == out[j].Score {
 			return strings.Compare(out[i].Name, out[j].Name) < 0
@@ -274,3 +403,203 @@ func SuggestTraitsBayes(m *Matrix, post []float64, tau float64, selected map[str
 	})
 	return out
 }
+
+// answerModel holds, for each answer s to a trait, the predictive probability
+// P(s | taxon) and the likelihood factor that answer applies to each taxon.
+// Neither depends on the posterior, so it is built once per trait.
+type answerModel struct {
+	pred   [][]float64
+	factor [][]float64
+}
+
+// buildAnswerModel scores every answer to d with the Bayes core's own
+// likelihood (BayesEvalParams.traitLogLik), so error rates, the NA penalty, the
+// conflict penalty, tempering and observation confidence all shape the expected
+// posteriors just as they will shape the ranking once the answer is given.
+func buildAnswerModel(m *Matrix, d stateDef, p BayesEvalParams, opt AlgoOptions) answerModel {
+	k := d.answers()
+	alpha, beta := p.errorRates(d.traitID)
+	lambda := p.Lambda
+	if lambda <= 0 {
+		lambda = 1.0
+	}
+	confidence := 1.0
+	for _, t := range m.Traits {
+		if t.ID == d.traitID {
+			confidence = observationConfidence(opt, t)
+			break
+		}
+	}
+
+	obs := make([]BayesObservation, k)
+	am := answerModel{pred: make([][]float64, k), factor: make([][]float64, k)}
+	for s := range obs {
+		obs[s] = d.answerObs(s, confidence)
+		am.pred[s] = make([]float64, len(m.Taxa))
+		am.factor[s] = make([]float64, len(m.Taxa))
+	}
+	for i := range m.Taxa {
+		truth := d.bayesTruth(&m.Taxa[i])
+		pred := d.predictive(truth, alpha, beta, opt)
+		for s := range obs {
+			am.pred[s][i] = pred[s]
+			am.factor[s][i] = d.answerLik(p, i, truth, obs[s], s, lambda)
+		}
+	}
+	return am
+}
+
+// maxContinuousBins caps the answers a continuous trait is split into.
+const maxContinuousBins = 8
+
+// continuousBinEdges returns the bin edges of a continuous trait: the distinct
+// range ends recorded across taxa, thinned to at most maxContinuousBins bins.
+func continuousBinEdges(m *Matrix, traitID string) []float64 {
+	seen := map[float64]bool{}
+	var ends []float64
+	for _, tx := range m.Taxa {
+		v, ok := tx.ContinuousTraits[traitID]
+		if !ok || tx.Inapplicable[traitID] {
+			continue
+		}
+		for _, x := range []float64{v.Min, v.Max} {
+			if !seen[x] {
+				seen[x] = true
+				ends = append(ends, x)
+			}
+		}
+	}
+	sort.Float64s(ends)
+	if len(ends)-1 <= maxContinuousBins {
+		return ends
+	}
+	edges := make([]float64, maxContinuousBins+1)
+	for b := range edges {
+		edges[b] = ends[b*(len(ends)-1)/maxContinuousBins]
+	}
+	return edges
+}
+
+func formatBin(lo, hi float64) string {
+	return strconv.FormatFloat(lo, 'g', 4, 64) + "–" + strconv.FormatFloat(hi, 'g', 4, 64)
+}
+
+// continuousBinMass returns the share of a taxon's values that falls in each
+// bin. The range model spreads them evenly over [Min, Max]; the normal models
+// use the same moments as the Bayes core, with the outer bins left open.
+func continuousBinMass(edges []float64, v ContinuousValue, opt AlgoOptions) []float64 {
+	k := len(edges) - 1
+	mass := make([]float64, k)
+	if opt.ContinuousAlgo == ContinuousGaussian || opt.ContinuousAlgo == ContinuousTruncatedNormal {
+		mu, sd := continuousMoments(BayesTruth{Min: v.Min, Max: v.Max, Mean: v.Mean, SD: v.SD}, opt.RangeCoverage)
+		cdf := func(x float64) float64 { return 0.5 * math.Erfc(-(x-mu)/(sd*math.Sqrt2)) }
+		prev := 0.0
+		for b := 0; b < k; b++ {
+			next := 1.0
+			if b < k-1 {
+				next = cdf(edges[b+1])
+			}
+			mass[b] = next - prev
+			prev = next
+		}
+		return mass
+	}
+	if v.Max <= v.Min {
+		b := 0
+		for b < k-1 && v.Min >= edges[b+1] {
+			b++
+		}
+		mass[b] = 1
+		return mass
+	}
+	for b := 0; b < k; b++ {
+		lo, hi := math.Max(v.Min, edges[b]), math.Min(v.Max, edges[b+1])
+		if hi > lo {
+			mass[b] = (hi - lo) / (v.Max - v.Min)
+		}
+	}
+	return mass
+}
+
+// continuousBinSamples is the number of values a bin is sampled at when its
+// likelihood is averaged.
+const continuousBinSamples = 5
+
+// answerLik is the tempered likelihood of answer s for taxon i. A continuous
+// answer only says which bin the measurement fell in, so its likelihood is
+// averaged over values spread evenly across the bin.
+func (d stateDef) answerLik(p BayesEvalParams, i int, truth BayesTruth, obs BayesObservation, s int, lambda float64) float64 {
+	if !d.continuous {
+		return math.Exp(lambda * p.traitLogLik(i, d.traitID, truth, obs))
+	}
+	lo, hi := d.edges[s], d.edges[s+1]
+	lik := 0.0
+	for j := 0; j < continuousBinSamples; j++ {
+		obs.Value = lo + (float64(j)+0.5)/continuousBinSamples*(hi-lo)
+		lik += math.Exp(lambda * p.traitLogLik(i, d.traitID, truth, obs))
+	}
+	return lik / continuousBinSamples
+}
+
+// outcomes returns the predictive probability of each answer under post and
+// the normalised posterior after that answer.
+func (am answerModel) outcomes(post []float64) (py []float64, postY [][]float64) {
+	py = make([]float64, len(am.pred))
+	postY = make([][]float64, len(am.pred))
+	for s := range am.pred {
+		postY[s] = make([]float64, len(post))
+		for i, p := range post {
+			py[s] += p * am.pred[s][i]
+			postY[s][i] = p * am.factor[s][i]
+		}
+		normalize(postY[s])
+	}
+	normalize(py)
+	return py, postY
+}
+
+// infoGain returns the expected IG of the trait at post, whose entropy is hNow,
+// without materialising the posteriors.
+func (am answerModel) infoGain(post []float64, hNow float64) float64 {
+	py := make([]float64, len(am.pred))
+	hy := make([]float64, len(am.pred))
+	for s := range am.pred {
+		// Entropy of the normalised w = post*factor is log Z - (Σ w log w)/Z.
+		z, wlogw := 0.0, 0.0
+		for i, p := range post {
+			py[s] += p * am.pred[s][i]
+			if w := p * am.factor[s][i]; w > 0 {
+				z += w
+				wlogw += w * math.Log2(w)
+			}
+		}
+		if z > 0 {
+			hy[s] = math.Log2(z) - wlogw/z
+		} else {
+			hy[s] = math.Log2(float64(len(post))) // normalize falls back to uniform
+		}
+	}
+	normalize(py)
+	ig := hNow
+	for s := range py {
+		ig -= py[s] * hy[s]
+	}
+	return ig
+}
+
+// traitCost returns the #Difficulty (default 1) and #Risk (clamped to [0,1]) of d.
+func traitCost(traitMeta map[string]Trait, d stateDef) (difficulty, risk float64) {
+	meta, ok := traitMeta[d.traitID]
+	if !ok {
+		meta, ok = traitMeta[d.name]
+	}
+	difficulty = 1.0
+	if ok {
+		difficulty = meta.Difficulty
+		if difficulty <= 0 {
+			difficulty = 1.0
+		}
+		risk = math.Max(0, math.Min(1, meta.Risk))
+	}
+	return difficulty, risk
+}
@@ -45,3 +45,14 @@ func countAbove(p []float64, thr float64) int {
 	}
 	return n
 }
+
+// 最大値（空なら0）
+func maxOf(p []float64) float64 {
+	m := 0.0
+	for _, v := range p {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
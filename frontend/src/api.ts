@@ -4,10 +4,12 @@ import { GetMatrix, GetTaxonDetails } from "../wailsjs/go/main/App";
 
 export type Choice = number;
 export type MultiChoice = string[];
+export type ContinuousObservation = { value: number; error?: number }; // Measured value with optional ± error
 
 export type Dependency = {
     parentTraitId: string;
     requiredState: string;
+    expr?: string; // Full #Dependency condition; compound conditions leave parentTraitId empty
 };
 
 export type MatrixInfo = {
@@ -84,9 +86,44 @@ export type Matrix = {
 };
 
 export type TaxonScore = engine.TaxonScore;
+
+// Probability summed by taxonomic rank (EvalResult.hierarchy).
+export type RankNode = {
+    rank: string;
+    name: string;
+    taxonId?: string;
+    post: number;
+    count: number;
+    children?: RankNode[];
+};
+export type Hierarchy = {
+    roots: RankNode[];
+    confident?: { rank: string; name: string; post: number; threshold: number };
+};
+// Smallest set of taxa covering the credible level, and the stop/continue verdict (EvalResult.credible / .verdict).
+export type CredibleSet = {
+    level: number;
+    taxonIds: string[];
+    mass: number;
+    reached: boolean;
+};
+export type StopVerdict = {
+    stop: boolean;
+    confidence: "absolute" | "very_high" | "high" | "contested" | "na";
+    reason: "single_candidate" | "credible_set" | "no_informative" | "continue" | "no_candidates";
+    setSize: number;
+    ratio: number;
+    bestIG: number;
+    hasIG: boolean;
+    level: number;
+    stopRatio: number;
+    minInfoGain: number;
+};
 export type StateProb = engine.StateProb;
 export type TraitSuggestion = engine.TraitSuggestion & {
     max_ig?: number;
+    expectedCost?: number; // "plan" strategy: expected total difficulty to finish
+    expectedSteps?: number; // ... and expected number of observations
 };
 export type ApplyOptions = main.ApplyOptions;
 export type ApplyResult = main.ApplyResultEx;
@@ -97,13 +134,15 @@ export type JustificationItem = {
     traitGroupName: string;
     userChoice: string;
     taxonState: string;
-    status: "match" | "conflict" | "neutral" | "unobserved";
+    status: "match" | "conflict" | "neutral" | "unobserved" | "unobservable" | "inapplicable";
 }
 
 export type Justification = {
     matches: JustificationItem[];
     conflicts: JustificationItem[];
     unobserved: JustificationItem[];
+    unobservable?: JustificationItem[]; // Traits marked NA (unobservable on this specimen)
+    inapplicable?: JustificationItem[]; // Dependents ruled out by the observed parent
     matchCount: number;
     conflictCount: number;
 }
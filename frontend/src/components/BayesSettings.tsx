@@ -2,7 +2,7 @@
 import React from "react";
 import {
   Dialog, DialogTitle, DialogContent, DialogActions,
-  Button, TextField, Stack, Typography
+  Button, TextField, Stack, Typography, FormControlLabel, Switch
 } from "@mui/material";
 import { AlgoOptions, clampAlgoOptions } from "../hooks/useAlgoOpts";
 
@@ -61,6 +61,26 @@ export default function BayesSettings(props: Props) {
             type="number" inputProps={{ step: 0.1, min: 0, max: 5 }}
             value={value.kappa} onChange={handleNum("kappa")}
           />
+          <TextField
+            label="λ (尤度の調整指数, 0–3)"
+            type="number" inputProps={{ step: 0.1, min: 0, max: 3 }}
+            value={value.lambda} onChange={handleNum("lambda")}
+          />
+          <FormControlLabel
+            control={<Switch checked={!!value.useVerifiedCases} onChange={(e) => onChange({ ...value, useVerifiedCases: e.target.checked })} />}
+            label="確認済みの同定事例で形質ごとの誤り率を更新する"
+          />
+          <TextField
+            label="誤り率の事前分布の強さ (回答数, 0–100)"
+            type="number" inputProps={{ step: 1, min: 0, max: 100 }}
+            disabled={!value.useVerifiedCases}
+            value={value.priorStrength} onChange={handleNum("priorStrength")}
+          />
+          <TextField
+            label="未知の分類群の事前確率 (0–0.5, 0で無効)"
+            type="number" inputProps={{ step: 0.01, min: 0, max: 0.5 }}
+            value={value.unknownPrior} onChange={handleNum("unknownPrior")}
+          />
           <TextField
             label="ε (小値丸め, 1e-12–1e-3)"
             type="number" inputProps={{ step: "any", min: 1e-12, max: 1e-3 }}
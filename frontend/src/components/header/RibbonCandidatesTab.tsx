@@ -1,14 +1,17 @@
 // frontend/src/components/header/RibbonCandidatesTab.tsx
-import React, { useMemo } from "react";
+import React, { useEffect, useMemo, useState } from "react";
 import {
   Box, Divider, FormControl, InputLabel,
-  MenuItem, Select, SelectChangeEvent, Slider, Stack, Typography, Button, Card, CardContent, CardHeader, Table, TableBody, TableCell, TableRow, TableHead
+  MenuItem, Select, SelectChangeEvent, Slider, Stack, Typography, Button, FormControlLabel, Switch, TextField, Card, CardContent, CardHeader, Table, TableBody, TableCell, TableRow, TableHead
 } from "@mui/material";
 import GavelIcon from '@mui/icons-material/Gavel';
 import ReportProblemIcon from '@mui/icons-material/ReportProblem';
 import ScienceIcon from '@mui/icons-material/Science';
 import { useAlgoOpts, AlgoOptions, clampAlgoOptions } from "../../hooks/useAlgoOpts";
+import BayesSettings from "../BayesSettings";
 import { STR } from "../../i18n";
+import { ListAlgorithms } from "../../../wailsjs/go/main/App";
+import { engine } from "../../../wailsjs/go/models";
 
 // A helper component for a setting item within a card
 const SettingItem = ({ title, description, effect, tradeoffs, children, lang = "ja" }: {
@@ -56,8 +59,8 @@ const SettingItem = ({ title, description, effect, tradeoffs, children, lang = "
 
 type Props = {
   matrixName: string;
-  algorithm?: "bayes" | "heuristic";
-  onAlgorithmChange?: (algo: "bayes" | "heuristic") => void;
+  algorithm?: string;
+  onAlgorithmChange?: (algo: string) => void;
   opts: AlgoOptions;
   setOpts: React.Dispatch<React.SetStateAction<AlgoOptions>>;
   lang: "ja" | "en";
@@ -73,18 +76,30 @@ export default function RibbonCandidatesTab(props: Props) {
   const T = STR[lang].candidatesTab;
   const { reset } = useAlgoOpts(matrixName);
   const saneOpts = useMemo(() => clampAlgoOptions(opts), [opts]);
+  const [algorithms, setAlgorithms] = useState<engine.AlgorithmInfo[]>([]);
+  const [advancedOpen, setAdvancedOpen] = useState(false);
+
+  // The choices come from the engine's scorer registry, so in-house scorers show up too.
+  useEffect(() => {
+    ListAlgorithms()
+      .then((list) => setAlgorithms(list || []))
+      .catch((error) => console.error("Failed to list algorithms:", error));
+  }, []);
+  const algoInfo = algorithms.find((a) => a.name === algorithm);
 
   return (
     <Box>
         <Stack direction="row" spacing={2} alignItems="center" sx={{ mb: 2 }}>
             <FormControl size="small" sx={{ minWidth: 200 }}>
             <InputLabel>{T.algorithm}</InputLabel>
-            <Select label={T.algorithm} value={algorithm} onChange={(e: SelectChangeEvent) => onAlgorithmChange?.(e.target.value as any)}>
-                <MenuItem value="bayes">Bayes</MenuItem>
-                <MenuItem value="heuristic">Heuristic</MenuItem>
+            <Select label={T.algorithm} value={algoInfo ? algorithm : ""} onChange={(e: SelectChangeEvent) => onAlgorithmChange?.(e.target.value)}>
+                {algorithms.map((a) => (
+                    <MenuItem key={a.name} value={a.name}>{a.label || a.name}</MenuItem>
+                ))}
             </Select>
             </FormControl>
             <Button variant="text" onClick={reset} >{T.reset_defaults}</Button>
+            {algorithm === 'bayes' && <Button variant="text" onClick={() => setAdvancedOpen(true)}>{T.advanced}</Button>}
         </Stack>
 
         {algorithm === 'bayes' ? (
@@ -98,6 +113,21 @@ export default function RibbonCandidatesTab(props: Props) {
                                 <Stack direction="row" justifyContent="space-between"><Typography variant="caption">0.0 (Lenient)</Typography><Typography variant="caption">1.0 (Strict)</Typography></Stack>
                             </Box>
                         </SettingItem>
+                        <Divider sx={{ my: 2 }} />
+                        <FormControlLabel
+                            control={<Switch checked={!!opts.tolerateConflicts} onChange={(_, checked) => setOpts(p => ({...p, tolerateConflicts: checked}))} />}
+                            label={<Typography variant="subtitle2">{T.param_tolerant.name}</Typography>}
+                        />
+                        <Box sx={{ px: 1, mt: 1 }}>
+                            <TextField
+                                size="small" type="number" label={T.param_tolerant.max}
+                                inputProps={{ step: 1, min: 0, max: 20 }}
+                                disabled={!opts.tolerateConflicts}
+                                value={saneOpts.maxConflicts}
+                                onChange={(e) => { const v = parseInt(e.target.value, 10); if (!Number.isNaN(v)) setOpts(p => ({...p, maxConflicts: v})); }}
+                            />
+                            <Typography variant="caption" color="text.secondary" component="p" sx={{ mt: 1 }}>{T.param_tolerant.description}</Typography>
+                        </Box>
                     </CardContent>
                 </Card>
 
@@ -148,9 +178,10 @@ export default function RibbonCandidatesTab(props: Props) {
             </Stack>
         ) : (
             <Typography variant="body2" color="text.secondary">
-                {lang === 'ja' ? 'ヒューリスティックモードは、矛盾のない候補を単純な一致率でスコアリングします。設定は不要です。' : 'Heuristic mode scores candidates by simple match rate, excluding those with conflicts. No parameters are needed.'}
+                {algoInfo?.description}
             </Typography>
         )}
+        <BayesSettings open={advancedOpen} onClose={() => setAdvancedOpen(false)} value={opts} onChange={setOpts} />
     </Box>
   );
-}
\ No newline at end of file
+}
\ No newline at end of file
@@ -12,8 +12,8 @@ import RibbonTraitRecommendTab from './RibbonTraitRecommendTab';
 
 type Props = {
   matrixName: string;
-  algorithm: "bayes" | "heuristic";
-  onAlgorithmChange: (algo: "bayes" | "heuristic") => void;
+  algorithm: string;
+  onAlgorithmChange: (algo: string) => void;
   opts: AlgoOptions;
   setOpts: React.Dispatch<React.SetStateAction<AlgoOptions>>;
   lang: "ja" | "en";
@@ -45,4 +45,4 @@ export default function RibbonSettingsTab(props: Props) {
       </Box>
     </Stack>
   );
-}
\ No newline at end of file
+}
\ No newline at end of file
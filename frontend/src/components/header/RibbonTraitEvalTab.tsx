@@ -1,6 +1,6 @@
 // frontend/src/components/header/RibbonTraitEvalTab.tsx
 import React, { useMemo } from "react";
-import { Box, Slider, Stack, Typography, RadioGroup, Radio, FormControl, Card, CardContent, CardHeader, FormControlLabel } from "@mui/material";
+import { Box, Slider, Stack, Typography, RadioGroup, Radio, FormControl, Card, CardContent, CardHeader, FormControlLabel, Select, MenuItem, TextField } from "@mui/material";
 import StraightenIcon from '@mui/icons-material/Straighten';
 import HubIcon from '@mui/icons-material/Hub';
 import { AlgoOptions, clampAlgoOptions } from "../../hooks/useAlgoOpts";
@@ -28,6 +28,27 @@ export default function RibbonTraitEvalTab({ opts, setOpts, lang }: Props) {
                 <Typography variant="subtitle2" gutterBottom>{T.param_tolerance.name}</Typography>
                 <Typography variant="caption" color="text.secondary" paragraph>{T.param_tolerance.description}</Typography>
                 <Slider value={saneOpts.toleranceFactor} onChange={(_, v) => setOpts(p => ({ ...p, toleranceFactor: v as number }))} min={0} max={0.5} step={0.01} valueLabelDisplay="auto" valueLabelFormat={v => `${(v*100).toFixed(0)}%`} />
+                <Box sx={{ mt: 2 }}>
+                    <Typography variant="subtitle2" gutterBottom>{T.continuous_algo.name}</Typography>
+                    <Typography variant="caption" color="text.secondary" paragraph>{T.continuous_algo.description}</Typography>
+                    <FormControl size="small" sx={{ minWidth: 200 }}>
+                        <Select value={opts.continuousAlgo ?? "range"} onChange={(e) => setOpts(p => ({ ...p, continuousAlgo: e.target.value as AlgoOptions["continuousAlgo"] }))}>
+                            <MenuItem value="range">{T.continuous_algo.options.range}</MenuItem>
+                            <MenuItem value="gaussian">{T.continuous_algo.options.gaussian}</MenuItem>
+                            <MenuItem value="truncated_normal">{T.continuous_algo.options.truncated_normal}</MenuItem>
+                        </Select>
+                    </FormControl>
+                </Box>
+                <Box sx={{ opacity: opts.continuousAlgo && opts.continuousAlgo !== 'range' ? 1 : 0.5, mt: 2 }}>
+                    <Typography variant="subtitle2" gutterBottom>{T.range_coverage.name}</Typography>
+                    <Typography variant="caption" color="text.secondary" paragraph>{T.range_coverage.description}</Typography>
+                    <TextField
+                        size="small" type="number" inputProps={{ step: 0.01, min: 0.5, max: 0.999 }}
+                        disabled={!opts.continuousAlgo || opts.continuousAlgo === 'range'}
+                        value={opts.rangeCoverage ?? 0.95}
+                        onChange={(e) => { const v = parseFloat(e.target.value); if (!Number.isNaN(v)) setOpts(p => ({ ...p, rangeCoverage: v })); }}
+                    />
+                </Box>
             </CardContent>
         </Card>
         
@@ -1,7 +1,7 @@
 // frontend/src/components/header/RibbonTraitRecommendTab.tsx
 import React from "react";
 import {
-  Box, FormControlLabel, Stack, Switch, Typography, RadioGroup, Radio, FormControl, Card, CardContent, CardHeader, Divider, Table, TableBody, TableCell, TableRow, TableHead
+  Box, FormControlLabel, Stack, Switch, Typography, RadioGroup, Radio, FormControl, Card, CardContent, CardHeader, Divider, Table, TableBody, TableCell, TableRow, TableHead, Slider
 } from "@mui/material";
 import RecommendIcon from '@mui/icons-material/Recommend';
 import LinkIcon from '@mui/icons-material/Link';
@@ -35,7 +35,15 @@ export default function RibbonTraitRecommendTab({ opts, setOpts, lang }: Props)
                     <RadioGroup row value={opts.recommendationStrategy} onChange={handleRadio("recommendationStrategy")}>
                         <FormControlLabel value="max_ig" control={<Radio size="small" />} label={T.recommendation_strategy.options.breakthrough} />
                         <FormControlLabel value="expected_ig" control={<Radio size="small" />} label={T.recommendation_strategy.options.stable} />
+                        <FormControlLabel value="plan" control={<Radio size="small" />} label={T.recommendation_strategy.options.plan} />
                     </RadioGroup>
+                    {opts.recommendationStrategy === "plan" && (
+                        <Box sx={{ px: 1 }}>
+                            <Typography variant="caption" color="text.secondary">{T.plan_depth.name}</Typography>
+                            <Slider size="small" min={1} max={3} step={1} marks value={opts.planDepth ?? 2} onChange={(_, v) => setOpts(p => ({ ...p, planDepth: v as number }))} valueLabelDisplay="auto" />
+                            <Typography variant="caption" color="text.secondary" sx={{display: 'block'}}>{T.plan_depth.description}</Typography>
+                        </Box>
+                    )}
                      <Box sx={{ p: 1.5, my: 1, borderRadius: 1, bgcolor: 'action.hover' }}>
                         <Table size="small" sx={{'.MuiTableCell-root': { p: 0.5, borderBottom: 'none', fontSize: '0.75rem' }}}>
                            <TableHead>
@@ -3,14 +3,23 @@ import React, { useState } from "react";
 import {
   Paper, Box, Typography, Table, TableHead, TableRow, TableCell,
   TableBody, TableContainer, LinearProgress, Tooltip, Chip,
-  Stack, Checkbox, Button, FormControlLabel, Switch, Modal, IconButton
+  Stack, Checkbox, Button, FormControlLabel, Switch, Modal, IconButton,
+  Accordion, AccordionSummary, AccordionDetails
 } from "@mui/material";
+import ExpandMoreIcon from '@mui/icons-material/ExpandMore';
 import CompareArrowsIcon from '@mui/icons-material/CompareArrows';
 import HelpOutlineIcon from '@mui/icons-material/HelpOutline';
+import TaskAltIcon from '@mui/icons-material/TaskAlt';
+import LeaderboardIcon from '@mui/icons-material/Leaderboard';
 import { STR } from "../../../i18n";
-import { Taxon, Justification, MultiChoice, Choice } from "../../../api";
-import { GetJustificationForTaxon } from "../../../../wailsjs/go/main/App";
+import { Taxon, Justification, MultiChoice, Choice, ContinuousObservation, StopVerdict } from "../../../api";
+import { GetJustificationForRequest } from "../../../../wailsjs/go/main/App";
+import { main } from "../../../../wailsjs/go/models";
 import JustificationPanel from "./JustificationPanel";
+import VerifiedCasesDialog from "./VerifiedCasesDialog";
+import EnsembleDialog from "./EnsembleDialog";
+import { EvalMode } from "../../../utils/applyFilters";
+import { AlgoOptions } from "../../../hooks/useAlgoOpts";
 import { FormattedTaxonName } from "../../common/FormattedTaxonName";
 
 export type EngineScore = {
@@ -30,13 +39,18 @@ type CandidatesPanelProps = {
   rows: EngineScore[];
   totalTaxa: number;
   lang?: "ja" | "en";
-  algo: "bayes" | "heuristic";
+  algo: string;
   comparisonList: string[];
   setComparisonList: React.Dispatch<React.SetStateAction<string[]>>;
   onCompareClick: () => void;
   onTaxonSelect: (taxon: Taxon) => void;
   selected: Record<string, Choice>;
   selectedMulti: Record<string, MultiChoice>;
+  selectedContinuous: Record<string, ContinuousObservation>;
+  opts: AlgoOptions;
+  verdict?: StopVerdict | null;
+  dropped?: EngineScore[]; // Taxa eliminated by the strict/tolerant mode
+  mode: EvalMode;
 };
 
 const ScoreCell = ({ score }: { score: number }) => (
@@ -55,15 +69,23 @@ const ScoreCell = ({ score }: { score: number }) => (
 export default function CandidatesPanel({
   title, rows, totalTaxa, lang = "ja", algo,
   comparisonList, setComparisonList, onCompareClick, onTaxonSelect,
-  selected, selectedMulti
+  selected, selectedMulti, selectedContinuous, opts, verdict, dropped = [], mode
 }: CandidatesPanelProps) { // 修正：型を適用
   const T = STR[lang].candidatesPanel;
+  const TV = STR[lang].verifiedCases;
   const [showMatchSupport, setShowMatchSupport] = useState<boolean>(false);
   
   const [justificationOpen, setJustificationOpen] = useState(false);
   const [currentJustification, setCurrentJustification] = useState<Justification | null>(null);
   const [loadingJustification, setLoadingJustification] = useState(false);
   const [currentTargetTaxon, setCurrentTargetTaxon] = useState<Taxon | null>(null);
+  const [verifiedOpen, setVerifiedOpen] = useState(false);
+  const [verifiedTaxon, setVerifiedTaxon] = useState<Taxon | null>(null);
+  const [ensembleOpen, setEnsembleOpen] = useState(false);
+
+  // The observations the ranking was computed from, as sent for justifications and confirmed cases
+  const currentRequest = () => new main.ApplyRequest({ selected, selectedMulti, selectedContinuous: { ...selectedContinuous }, mode, opts });
+  const hasObservations = Object.keys(selected).length > 0 || Object.keys(selectedMulti).length > 0 || Object.keys(selectedContinuous).length > 0;
 
   const handleCompareChange = (taxonId: string, checked: boolean) => {
     if (checked) {
@@ -88,7 +110,7 @@ export default function CandidatesPanel({
       setJustificationOpen(true);
       setCurrentTargetTaxon(taxon);
       try {
-          const result = await GetJustificationForTaxon(taxon.id, selected, selectedMulti);
+          const result = await GetJustificationForRequest(taxon.id, currentRequest());
           setCurrentJustification(result as Justification);
       } catch (error) {
           console.error("Failed to get justification:", error);
@@ -97,10 +119,24 @@ export default function CandidatesPanel({
       }
   };
 
+  const handleRecordClick = (e: React.MouseEvent, taxon: Taxon | null) => {
+      e.stopPropagation();
+      setVerifiedTaxon(taxon);
+      setVerifiedOpen(true);
+  };
 
   const scoreHeader = algo === 'bayes' ? T.header_post_prob : T.header_score;
   const scoreTooltip = algo === 'bayes' ? T.tooltip_post : T.tooltip_score;
   
+  const verdictLabel = !verdict || verdict.reason === "no_candidates" ? null
+    : verdict.reason === "no_informative" ? T.verdict_no_informative
+    : verdict.stop ? T.verdict_stop : T.verdict_continue;
+  const verdictTooltip = verdict ? T.verdict_tooltip
+    .replace('{level}', `${(verdict.level * 100).toFixed(0)}%`)
+    .replace('{size}', String(verdict.setSize))
+    .replace('{ratio}', verdict.ratio > 0 ? verdict.ratio.toFixed(2) : '∞')
+    .replace('{ig}', verdict.hasIG ? verdict.bestIG.toFixed(3) : '-') : '';
+
   const numSelected = comparisonList.length;
   const rowCount = rows.length;
   const isAllSelected = rowCount > 0 && numSelected === rowCount;
@@ -112,12 +148,24 @@ export default function CandidatesPanel({
         <Stack direction="row" alignItems="center" spacing={1}>
             <Typography variant="h6">{title || STR[lang].panels.candidates}</Typography>
             <Chip label={`${rows?.length ?? 0} / ${totalTaxa}`} size="small" />
+            {verdictLabel && (
+                <Tooltip title={verdictTooltip}>
+                    <Chip label={verdictLabel} size="small" color={verdict?.stop ? "success" : "default"} variant={verdict?.stop ? "filled" : "outlined"} />
+                </Tooltip>
+            )}
             <FormControlLabel 
                 control={<Switch size="small" checked={showMatchSupport} onChange={e => setShowMatchSupport(e.target.checked)}/>}
                 label={<Typography variant="body2">{T.show_match_support}</Typography>}
                 sx={{ ml: 1 }}
             />
         </Stack>
+        <Stack direction="row" spacing={1}>
+        <Button variant="text" size="small" startIcon={<LeaderboardIcon/>} onClick={() => setEnsembleOpen(true)}>
+            {STR[lang].ensemble.open_button}
+        </Button>
+        <Button variant="text" size="small" startIcon={<TaskAltIcon/>} onClick={(e) => handleRecordClick(e, null)}>
+            {TV.open_button}
+        </Button>
         <Button
             variant="outlined"
             size="small"
@@ -127,6 +175,7 @@ export default function CandidatesPanel({
         >
             {T.compare_button} ({comparisonList.length})
         </Button>
+        </Stack>
       </Stack>
 
       <TableContainer component={Box} sx={{ flex: 1 }}>
@@ -149,7 +198,7 @@ export default function CandidatesPanel({
               <TableCell sx={{ width: 80 }}><Tooltip title={T.tooltip_delta}><span>{T.header_delta}</span></Tooltip></TableCell>
               <TableCell sx={{ width: 70 }} align="center"><Tooltip title={<Typography sx={{whiteSpace: 'pre-line'}}>{T.tooltip_conflicts}</Typography>}><span>{T.header_conflicts}</span></Tooltip></TableCell>
               {showMatchSupport && <TableCell sx={{ width: 110 }}><Tooltip title={<Typography sx={{whiteSpace: 'pre-line'}}>{T.tooltip_match_support}</Typography>}><span>{T.header_match_support}</span></Tooltip></TableCell>}
-              <TableCell sx={{ width: 50 }} align="center">{T.header_why}</TableCell>
+              <TableCell sx={{ width: 90 }} align="center">{T.header_why}</TableCell>
             </TableRow>
           </TableHead>
           <TableBody>
@@ -190,17 +239,50 @@ export default function CandidatesPanel({
                 <TableCell align="center">
                     <Tooltip title={`${T.tooltip_why_prefix} ${r.taxon?.name}`}>
                         <span>
-                        <IconButton size="small" onClick={(e) => handleWhyClick(e, r.taxon)} disabled={!selected || Object.keys(selected).length === 0 && Object.keys(selectedMulti).length === 0}>
+                        <IconButton size="small" onClick={(e) => handleWhyClick(e, r.taxon)} disabled={!hasObservations}>
                             <HelpOutlineIcon fontSize="small"/>
                         </IconButton>
                         </span>
                     </Tooltip>
+                    <Tooltip title={TV.record_tooltip}>
+                        <span>
+                        <IconButton size="small" onClick={(e) => handleRecordClick(e, r.taxon)} disabled={!hasObservations}>
+                            <TaskAltIcon fontSize="small"/>
+                        </IconButton>
+                        </span>
+                    </Tooltip>
                 </TableCell>
               </TableRow>
             )})}
           </TableBody>
         </Table>
       </TableContainer>
+      {dropped.length > 0 && (
+        <Accordion disableGutters variant="outlined" sx={{ mt: 1 }}>
+          <AccordionSummary expandIcon={<ExpandMoreIcon />}>
+            <Typography variant="body2">{T.eliminated.replace('{n}', String(dropped.length))}</Typography>
+          </AccordionSummary>
+          <AccordionDetails sx={{ p: 0, maxHeight: 200, overflow: 'auto' }}>
+            <Table size="small">
+              <TableBody>
+                {dropped.map((r, i) => (
+                  <TableRow key={r.taxon?.id || `dropped-${i}`} hover onClick={() => onTaxonSelect(r.taxon)} sx={{ cursor: 'pointer' }}>
+                    <TableCell><FormattedTaxonName taxon={r.taxon} lang={lang} /></TableCell>
+                    <TableCell sx={{ width: 70 }} align="center"><Chip label={r.conflicts ?? 0} color="error" size="small" /></TableCell>
+                    <TableCell sx={{ width: 50 }} align="center">
+                      <Tooltip title={`${T.tooltip_why_prefix} ${r.taxon?.name}`}>
+                        <IconButton size="small" onClick={(e) => handleWhyClick(e, r.taxon)}>
+                          <HelpOutlineIcon fontSize="small"/>
+                        </IconButton>
+                      </Tooltip>
+                    </TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </AccordionDetails>
+        </Accordion>
+      )}
     </Paper>
     <Modal open={justificationOpen} onClose={() => setJustificationOpen(false)}>
         <Box sx={{
@@ -227,6 +309,20 @@ export default function CandidatesPanel({
             />
         </Box>
     </Modal>
+    <VerifiedCasesDialog
+        open={verifiedOpen}
+        onClose={() => setVerifiedOpen(false)}
+        taxon={verifiedTaxon}
+        request={currentRequest()}
+        opts={opts}
+        lang={lang}
+    />
+    <EnsembleDialog
+        open={ensembleOpen}
+        onClose={() => setEnsembleOpen(false)}
+        request={currentRequest()}
+        lang={lang}
+    />
     </>
   );
-}
\ No newline at end of file
+}
\ No newline at end of file
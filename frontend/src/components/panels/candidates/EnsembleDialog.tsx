@@ -0,0 +1,108 @@
+// frontend/src/components/panels/candidates/EnsembleDialog.tsx
+import React, { useEffect, useState } from 'react';
+import {
+    Dialog, DialogTitle, DialogContent, DialogActions, Alert,
+    Button, Stack, Typography, Chip, Tooltip, FormControlLabel, Switch, CircularProgress,
+    Table, TableBody, TableCell, TableContainer, TableHead, TableRow
+} from '@mui/material';
+import WarningAmberIcon from '@mui/icons-material/WarningAmber';
+import { STR } from '../../../i18n';
+import { ApplyEnsemble } from '../../../../wailsjs/go/main/App';
+import { engine, main } from '../../../../wailsjs/go/models';
+import { FormattedTaxonName } from '../../common/FormattedTaxonName';
+
+type Props = {
+    open: boolean;
+    onClose: () => void;
+    request: main.ApplyRequest;
+    lang: "ja" | "en";
+};
+
+export default function EnsembleDialog({ open, onClose, request, lang }: Props) {
+    const T = STR[lang].ensemble;
+    const [result, setResult] = useState<engine.EnsembleResult | null>(null);
+    const [loading, setLoading] = useState(false);
+    const [error, setError] = useState<string | null>(null);
+    const [onlyDisagreements, setOnlyDisagreements] = useState(false);
+
+    // The request is rebuilt on every render of the parent, so only opening the dialog runs the ensemble.
+    useEffect(() => {
+        if (!open) return;
+        setLoading(true);
+        setError(null);
+        ApplyEnsemble(request)
+            .then((res) => setResult(res))
+            .catch((e) => setError(String(e)))
+            .finally(() => setLoading(false));
+        // eslint-disable-next-line react-hooks/exhaustive-deps
+    }, [open]);
+
+    const algos = (result?.runs || []).map((r) => r.algo);
+    const entries = (result?.consensus || []).filter((e) => !onlyDisagreements || e.disagreement);
+    const disagreements = (result?.consensus || []).filter((e) => e.disagreement).length;
+
+    return (
+        <Dialog open={open} onClose={onClose} maxWidth="lg" fullWidth>
+            <DialogTitle>{T.title}</DialogTitle>
+            <DialogContent>
+                <Stack spacing={2} mt={1}>
+                    <Typography variant="caption" color="text.secondary">{T.description}</Typography>
+                    {error && <Alert severity="error">{error}</Alert>}
+                    <Stack direction="row" spacing={2} alignItems="center">
+                        <Chip size="small" icon={<WarningAmberIcon />} color={disagreements > 0 ? "warning" : "default"} label={T.disagreement_count.replace('{n}', String(disagreements))} />
+                        <FormControlLabel
+                            control={<Switch size="small" checked={onlyDisagreements} onChange={(e) => setOnlyDisagreements(e.target.checked)} />}
+                            label={<Typography variant="body2">{T.only_disagreements}</Typography>}
+                        />
+                    </Stack>
+                    {loading ? (
+                        <Stack alignItems="center" sx={{ py: 4 }}><CircularProgress /></Stack>
+                    ) : (
+                        <TableContainer sx={{ maxHeight: '60vh', border: 1, borderColor: 'divider', borderRadius: 1 }}>
+                            <Table size="small" stickyHeader>
+                                <TableHead>
+                                    <TableRow>
+                                        <TableCell sx={{ width: 60 }}><Tooltip title={T.tooltip_consensus}><span>{T.header_consensus}</span></Tooltip></TableCell>
+                                        <TableCell>{T.header_name}</TableCell>
+                                        {algos.map((a) => <TableCell key={a} align="center">{a}</TableCell>)}
+                                        <TableCell align="center">{T.header_mean_rank}</TableCell>
+                                        <TableCell align="center"><Tooltip title={T.tooltip_spread}><span>{T.header_spread}</span></Tooltip></TableCell>
+                                    </TableRow>
+                                </TableHead>
+                                <TableBody>
+                                    {entries.map((e) => (
+                                        <TableRow key={e.taxon.id} sx={{ bgcolor: e.disagreement ? 'rgba(255, 193, 7, 0.1)' : 'transparent' }}>
+                                            <TableCell>{e.consensusRank}</TableCell>
+                                            <TableCell><FormattedTaxonName taxon={e.taxon as any} lang={lang} typographyVariant="body2" /></TableCell>
+                                            {algos.map((a) => (
+                                                <TableCell key={a} align="center">
+                                                    <Tooltip title={(e.scores?.[a] ?? 0).toPrecision(3)}><span>{e.ranks?.[a] ?? '-'}</span></Tooltip>
+                                                </TableCell>
+                                            ))}
+                                            <TableCell align="center">{e.meanRank.toFixed(1)}</TableCell>
+                                            <TableCell align="center">
+                                                {e.disagreement
+                                                    ? <Chip size="small" color="warning" icon={<WarningAmberIcon />} label={e.rankSpread} />
+                                                    : <Typography variant="caption" color="text.secondary">{e.rankSpread}</Typography>}
+                                            </TableCell>
+                                        </TableRow>
+                                    ))}
+                                    {entries.length === 0 && (
+                                        <TableRow>
+                                            <TableCell colSpan={algos.length + 4} align="center">
+                                                <Typography variant="caption" color="text.secondary">{T.none}</Typography>
+                                            </TableCell>
+                                        </TableRow>
+                                    )}
+                                </TableBody>
+                            </Table>
+                        </TableContainer>
+                    )}
+                </Stack>
+            </DialogContent>
+            <DialogActions>
+                <Button onClick={onClose}>{T.close}</Button>
+            </DialogActions>
+        </Dialog>
+    );
+}
@@ -8,6 +8,8 @@ import CloseIcon from '@mui/icons-material/Close';
 import CheckCircleIcon from '@mui/icons-material/CheckCircle';
 import CancelIcon from '@mui/icons-material/Cancel';
 import HelpIcon from '@mui/icons-material/Help';
+import VisibilityOffIcon from '@mui/icons-material/VisibilityOff';
+import BlockIcon from '@mui/icons-material/Block';
 import { Taxon, Justification, JustificationItem } from '../../../api';
 import { STR } from '../../../i18n';
 import { FormattedTaxonName } from '../../common/FormattedTaxonName';
@@ -64,6 +66,8 @@ const JustificationTable = ({ title, items, icon, color, lang }: { title: string
 
 export default function JustificationPanel({ taxon, justification, loading, onClose, lang }: Props) {
     const T = STR[lang].justificationPanel;
+    const unobservable = justification?.unobservable || [];
+    const inapplicable = justification?.inapplicable || [];
 
     if (loading) {
         return <Box sx={{ display: 'flex', alignItems: 'center', justifyContent: 'center', height: '100%' }}><CircularProgress /></Box>;
@@ -88,12 +92,16 @@ export default function JustificationPanel({ taxon, justification, loading, onCl
                 <Chip label={`${T.matches}: ${justification.matchCount}`} color="success" size="small" icon={<CheckCircleIcon />} />
                 <Chip label={`${T.conflicts}: ${justification.conflictCount}`} color="error" size="small" icon={<CancelIcon />} />
                 <Chip label={`${T.unobserved}: ${justification.unobserved.length}`} size="small" icon={<HelpIcon />} />
+                {unobservable.length > 0 && <Chip label={`${T.unobservable}: ${unobservable.length}`} size="small" variant="outlined" icon={<VisibilityOffIcon />} />}
+                {inapplicable.length > 0 && <Chip label={`${T.inapplicable}: ${inapplicable.length}`} size="small" variant="outlined" icon={<BlockIcon />} />}
             </Stack>
             <Divider sx={{ my: 1 }}/>
             <Stack direction={{xs: 'column', md: 'row'}} spacing={2} sx={{ flex: 1, minHeight: 0, mt: 1 }}>
                 <JustificationTable title={T.matches} items={justification.matches} icon={<CheckCircleIcon />} color="success.main" lang={lang} />
                 <JustificationTable title={T.conflicts} items={justification.conflicts} icon={<CancelIcon />} color="error.main" lang={lang} />
                 <JustificationTable title={T.unobserved} items={justification.unobserved} icon={<HelpIcon />} color="text.secondary" lang={lang} />
+                {unobservable.length > 0 && <JustificationTable title={T.unobservable} items={unobservable} icon={<VisibilityOffIcon />} color="text.secondary" lang={lang} />}
+                {inapplicable.length > 0 && <JustificationTable title={T.inapplicable} items={inapplicable} icon={<BlockIcon />} color="text.secondary" lang={lang} />}
             </Stack>
         </>
     );
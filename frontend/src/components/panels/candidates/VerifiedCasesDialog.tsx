@@ -0,0 +1,174 @@
+// frontend/src/components/panels/candidates/VerifiedCasesDialog.tsx
+import React, { useCallback, useEffect, useState } from 'react';
+import {
+    Dialog, DialogTitle, DialogContent, DialogActions, Alert,
+    Button, TextField, Stack, Typography, IconButton, Tooltip,
+    Table, TableBody, TableCell, TableContainer, TableHead, TableRow
+} from '@mui/material';
+import DeleteIcon from '@mui/icons-material/Delete';
+import { Taxon } from '../../../api';
+import { STR } from '../../../i18n';
+import { AddVerifiedCase, DeleteVerifiedCase, ListVerifiedCases, SuggestErrorRateUpdates } from '../../../../wailsjs/go/main/App';
+import { engine, main } from '../../../../wailsjs/go/models';
+import { AlgoOptions } from '../../../hooks/useAlgoOpts';
+import { FormattedTaxonName } from '../../common/FormattedTaxonName';
+
+type Props = {
+    open: boolean;
+    onClose: () => void;
+    taxon: Taxon | null; // Taxon the current observations are recorded for; null only lists the stored cases
+    request: main.ApplyRequest;
+    opts: AlgoOptions;
+    lang: "ja" | "en";
+};
+
+const fmtRate = (v?: number) => (v && v > 0 ? v.toFixed(3) : '-');
+
+export default function VerifiedCasesDialog({ open, onClose, taxon, request, opts, lang }: Props) {
+    const T = STR[lang].verifiedCases;
+    const [cases, setCases] = useState<main.VerifiedCase[]>([]);
+    const [suggestions, setSuggestions] = useState<engine.TraitCalibration[]>([]);
+    const [note, setNote] = useState("");
+    const [recorded, setRecorded] = useState(false);
+    const [error, setError] = useState<string | null>(null);
+
+    const refresh = useCallback(async () => {
+        try {
+            const [list, calibration] = await Promise.all([
+                ListVerifiedCases(),
+                SuggestErrorRateUpdates(opts.priorStrength ?? 2, opts.defaultAlphaFP, opts.defaultBetaFN),
+            ]);
+            setCases(list || []);
+            setSuggestions(calibration || []);
+            setError(null);
+        } catch (e) {
+            setError(String(e));
+        }
+    }, [opts.priorStrength, opts.defaultAlphaFP, opts.defaultBetaFN]);
+
+    useEffect(() => {
+        if (!open) return;
+        setNote("");
+        setRecorded(false);
+        refresh();
+    }, [open, taxon, refresh]);
+
+    const handleRecord = async () => {
+        if (!taxon) return;
+        try {
+            await AddVerifiedCase(taxon.id, request, note);
+            setRecorded(true);
+            await refresh();
+        } catch (e) {
+            setError(String(e));
+        }
+    };
+
+    const handleDelete = async (index: number) => {
+        try {
+            await DeleteVerifiedCase(index);
+            await refresh();
+        } catch (e) {
+            setError(String(e));
+        }
+    };
+
+    return (
+        <Dialog open={open} onClose={onClose} maxWidth="md" fullWidth>
+            <DialogTitle>{T.title}</DialogTitle>
+            <DialogContent>
+                <Stack spacing={2} mt={1}>
+                    {error && <Alert severity="error">{error}</Alert>}
+
+                    {taxon && (
+                        <Stack direction="row" spacing={2} alignItems="center">
+                            <Typography variant="body2">{T.record_prompt}</Typography>
+                            <FormattedTaxonName taxon={taxon} lang={lang} typographyVariant="body2" />
+                            <TextField size="small" label={T.note} value={note} onChange={(e) => setNote(e.target.value)} disabled={recorded} sx={{ flex: 1 }} />
+                            <Button variant="contained" onClick={handleRecord} disabled={recorded}>{recorded ? T.recorded : T.record}</Button>
+                        </Stack>
+                    )}
+
+                    <Typography variant="subtitle2">{T.cases_title} ({cases.length})</Typography>
+                    <TableContainer sx={{ maxHeight: 200, border: 1, borderColor: 'divider', borderRadius: 1 }}>
+                        <Table size="small" stickyHeader>
+                            <TableHead>
+                                <TableRow>
+                                    <TableCell>{T.header_taxon}</TableCell>
+                                    <TableCell>{T.header_recorded}</TableCell>
+                                    <TableCell>{T.note}</TableCell>
+                                    <TableCell sx={{ width: 50 }} />
+                                </TableRow>
+                            </TableHead>
+                            <TableBody>
+                                {cases.map((c, i) => (
+                                    <TableRow key={`${c.recordedAt}-${i}`}>
+                                        <TableCell>{c.taxonId}</TableCell>
+                                        <TableCell>{new Date(c.recordedAt).toLocaleString(lang)}</TableCell>
+                                        <TableCell>{c.note}</TableCell>
+                                        <TableCell align="center">
+                                            <Tooltip title={T.delete}>
+                                                <IconButton size="small" onClick={() => handleDelete(i)}><DeleteIcon fontSize="small" /></IconButton>
+                                            </Tooltip>
+                                        </TableCell>
+                                    </TableRow>
+                                ))}
+                                {cases.length === 0 && (
+                                    <TableRow>
+                                        <TableCell colSpan={4} align="center">
+                                            <Typography variant="caption" color="text.secondary">{T.none}</Typography>
+                                        </TableCell>
+                                    </TableRow>
+                                )}
+                            </TableBody>
+                        </Table>
+                    </TableContainer>
+
+                    <Typography variant="subtitle2">{T.suggestions_title}</Typography>
+                    <Typography variant="caption" color="text.secondary">{T.suggestions_description}</Typography>
+                    <TableContainer sx={{ maxHeight: 300, border: 1, borderColor: 'divider', borderRadius: 1 }}>
+                        <Table size="small" stickyHeader>
+                            <TableHead>
+                                <TableRow>
+                                    <TableCell>{T.header_trait}</TableCell>
+                                    <TableCell>#AlphaFP</TableCell>
+                                    <TableCell>#BetaFN</TableCell>
+                                    <TableCell>#Risk</TableCell>
+                                </TableRow>
+                            </TableHead>
+                            <TableBody>
+                                {suggestions.map((s) => (
+                                    <TableRow key={s.id}>
+                                        <TableCell>
+                                            <Typography variant="caption" color="text.secondary">{s.traitId}</Typography>
+                                            <Typography variant="body2">{lang === 'ja' ? s.name_jp || s.name_en : s.name_en || s.name_jp}</Typography>
+                                        </TableCell>
+                                        <TableCell>
+                                            {fmtRate(s.alphaFP)} → {fmtRate(s.suggestedAlphaFP)}
+                                            <Typography variant="caption" color="text.secondary" display="block">{s.counts.falsePositives}/{s.counts.negatives}</Typography>
+                                        </TableCell>
+                                        <TableCell>
+                                            {fmtRate(s.betaFN)} → {fmtRate(s.suggestedBetaFN)}
+                                            <Typography variant="caption" color="text.secondary" display="block">{s.counts.falseNegatives}/{s.counts.positives}</Typography>
+                                        </TableCell>
+                                        <TableCell>{fmtRate(s.risk)} → {s.suggestedRisk.toFixed(2)} ({s.riskLabel})</TableCell>
+                                    </TableRow>
+                                ))}
+                                {suggestions.length === 0 && (
+                                    <TableRow>
+                                        <TableCell colSpan={4} align="center">
+                                            <Typography variant="caption" color="text.secondary">{T.none}</Typography>
+                                        </TableCell>
+                                    </TableRow>
+                                )}
+                            </TableBody>
+                        </Table>
+                    </TableContainer>
+                </Stack>
+            </DialogContent>
+            <DialogActions>
+                <Button onClick={onClose}>{T.close}</Button>
+            </DialogActions>
+        </Dialog>
+    );
+}
@@ -19,6 +19,7 @@ import CloseIcon from '@mui/icons-material/Close';
 import { Trait, Taxon } from "../../../api";
 import { STR } from "../../../i18n";
 import { FormattedTaxonName } from "../../common/FormattedTaxonName";
+import { traitState } from "../../../utils/traitState";
 
 type Props = {
   lang: "ja" | "en";
@@ -28,32 +29,10 @@ type Props = {
   onClose: () => void;
 };
 
-const getTraitState = (taxon: Taxon, trait: Trait, allTraits: Trait[]): string => {
-    if (trait.type === 'binary') {
-        const state = taxon.traits?.[trait.id];
-        switch (state) {
-            case 1: return "Yes";
-            case -1: return "No";
-            default: return "NA";
-        }
-    }
-
-    if (trait.type === 'nominal_parent') {
-        const childTraits = allTraits.filter(t => t.parent === trait.traitId);
-        
-        for (const child of childTraits) {
-            if (taxon.traits?.[child.id] === 1) {
-                return child.state || child.name_en; 
-            }
-        }
-    }
-    
-    return "NA";
-};
-
 export default function ComparisonPanel({ lang, allTraits, allTaxa, comparisonList, onClose }: Props) {
   const T = STR[lang].comparisonPanel;
   const [hideSame, setHideSame] = useState(true);
+  const getTraitState = (taxon: Taxon, trait: Trait) => traitState(taxon, trait, allTraits, T.state_variable);
 
   const comparedTaxa = useMemo(() => {
     return allTaxa.filter(t => comparisonList.includes(t.id));
@@ -67,15 +46,15 @@ export default function ComparisonPanel({ lang, allTraits, allTaxa, comparisonLi
     }
 
     return userSelectableTraits.filter(trait => {
-        const firstState = getTraitState(comparedTaxa[0], trait, allTraits);
+        const firstState = getTraitState(comparedTaxa[0], trait);
         for (let i = 1; i < comparedTaxa.length; i++) {
-            if (getTraitState(comparedTaxa[i], trait, allTraits) !== firstState) {
+            if (getTraitState(comparedTaxa[i], trait) !== firstState) {
                 return true;
             }
         }
         return false;
     });
-  }, [allTraits, comparedTaxa, hideSame]);
+  }, [allTraits, comparedTaxa, hideSame, T.state_variable]);
 
   if (comparedTaxa.length < 2) {
     return (
@@ -113,7 +92,7 @@ export default function ComparisonPanel({ lang, allTraits, allTaxa, comparisonLi
           </TableHead>
           <TableBody>
             {displayTraits.map(trait => {
-                const states = comparedTaxa.map(taxon => getTraitState(taxon, trait, allTraits));
+                const states = comparedTaxa.map(taxon => getTraitState(taxon, trait));
                 const areDifferent = new Set(states).size > 1;
 
                 return (
@@ -2,7 +2,9 @@
 import React, { useEffect, useState } from 'react';
 import { Box, Typography, CircularProgress, Paper, Grid, Modal, IconButton, Tabs, Tab, Divider, List, ListItem, ListItemText, Chip } from '@mui/material';
 import CloseIcon from '@mui/icons-material/Close';
-import { Taxon } from '../../../api';
+import { Taxon, Trait } from '../../../api';
+import { STR } from '../../../i18n';
+import { traitState } from '../../../utils/traitState';
 import { GetHelpImage } from '../../../../wailsjs/go/main/App.js';
 // 修正：新しい共通コンポーネントをインポート
 import { FormattedTaxonName } from '../../common/FormattedTaxonName';
@@ -42,7 +44,7 @@ const HtmlRenderer: React.FC<{ content: string }> = ({ content }) => {
 
 // --- 修正：ファイル内にあったFormattedTaxonNameの定義は完全に削除 ---
 
-export default function TaxonDetailPanel({ taxon, lang }: { taxon: Taxon, lang: "ja" | "en" }) {
+export default function TaxonDetailPanel({ taxon, lang, traits = [] }: { taxon: Taxon, lang: "ja" | "en", traits?: Trait[] }) {
   const [modalOpen, setModalOpen] = useState(false);
   const [modalImage, setModalImage] = useState<string | null>(null);
   
@@ -52,6 +54,7 @@ export default function TaxonDetailPanel({ taxon, lang }: { taxon: Taxon, lang:
   const tabs = [];
   if (description) tabs.push(lang === 'ja' ? "解説" : "Description");
   tabs.push(lang === 'ja' ? "分類" : "Taxonomy");
+  if (traits.length > 0) tabs.push(lang === 'ja' ? "形質" : "Traits");
   if (taxon.images && taxon.images.length > 0 && taxon.images.filter(img => img.trim() !== "").length > 0) tabs.push(lang === 'ja' ? "画像" : "Images");
   if (references) tabs.push(lang === 'ja' ? "文献" : "References");
 
@@ -110,6 +113,18 @@ export default function TaxonDetailPanel({ taxon, lang }: { taxon: Taxon, lang:
                     ))}
                 </List>
             )}
+            {tabs[activeTab] === (lang === 'ja' ? "形質" : "Traits") && (
+                <List dense>
+                    {traits.filter(t => t.type === 'binary' || t.type === 'nominal_parent').map(trait => (
+                        <ListItem key={trait.id} sx={{py: 0.5}}>
+                            <ListItemText
+                                primary={lang === 'ja' ? trait.name_jp || trait.name_en : trait.name_en || trait.name_jp}
+                                secondary={traitState(taxon, trait, traits, STR[lang].comparisonPanel.state_variable)}
+                            />
+                        </ListItem>
+                    ))}
+                </List>
+            )}
             {tabs[activeTab] === (lang === 'ja' ? "文献" : "References") && <HtmlRenderer content={references || ''} />}
             {tabs[activeTab] === (lang === 'ja' ? "画像" : "Images") && taxon.images && (
                     <Grid container spacing={2}>
@@ -2,14 +2,14 @@ import React, { useState, useEffect, useMemo } from "react";
 import {
   Paper, Box, Typography, Stack, Button, ButtonGroup, Chip, Table, TableHead,
   TableRow, TableCell, TableContainer, Tooltip,
-  TableBody, Slider, TextField, IconButton
+  TableBody, Slider, TextField, IconButton, ToggleButton, ToggleButtonGroup
 } from "@mui/material";
 import DescriptionIcon from '@mui/icons-material/Description';
 import ImageIcon from '@mui/icons-material/Image';
 import ClearIcon from '@mui/icons-material/Clear';
 import CheckCircleOutlineIcon from '@mui/icons-material/CheckCircleOutline';
 import { STR } from "../../../i18n";
-import { Trait, TraitSuggestion, MultiChoice, Choice } from "../../../api";
+import { Trait, TraitSuggestion, MultiChoice, Choice, ContinuousObservation } from "../../../api";
 import { useMatrix } from "../../../hooks/useMatrix";
 
 export type TraitRow =
@@ -23,8 +23,10 @@ type Props = {
   rows: TraitRow[];
   selected: Record<string, Choice>;
   selectedMulti: Record<string, MultiChoice>;
+  selectedContinuous: Record<string, ContinuousObservation>;
+  inactive: string[]; // Trait IDs the engine found inapplicable under #Dependency
   setBinary: (traitId: string, val: Choice | null, label: string) => void;
-  setContinuous: (traitId: string, val: number | null, label: string) => void;
+  setContinuous: (traitId: string, obs: ContinuousObservation | null, label: string) => void;
   setMulti: (traitId: string, values: MultiChoice, label: string) => void;
   setMultiAsNA: (traitId: string, label?: string) => void;
   setDerivedPick: (childrenIds: string[], chosenId: string, parentLabel: string) => void;
@@ -32,9 +34,14 @@ type Props = {
   sortBy: "recommend" | "group" | "name";
   suggMap: Record<string, TraitSuggestion>;
   onTraitSelect: (trait?: Trait) => void;
+  confidence: Record<string, number>; // opts.confidence: hedged answers by trait key
+  setConfidence: (traitKey: string, value: number | null) => void;
   lang?: "ja" | "en";
 };
 
+// Confidence a "probably" answer is given; a sure answer has no entry (1).
+const HEDGE_PROBABLY = 0.7;
+
 // =============================================================================
 // Helper Components (Unchanged)
 // =============================================================================
@@ -52,7 +59,8 @@ const ScoreBar = React.memo(({ suggestion }: { suggestion?: TraitSuggestion }) =
     const ig = suggestion.ig > 0 ? suggestion.ig : 1;
     const normalizedScore = Math.max(0, baseScore / ig);
     const w = Math.max(2, Math.min(100, Math.round(normalizedScore * 100)));
-    const tooltipTitle = `Score: ${suggestion.score.toFixed(3)} (IG: ${suggestion.ig?.toFixed(3) ?? 'N/A'} / MaxIG: ${suggestion.max_ig?.toFixed(3) ?? 'N/A'})`;
+    const plan = suggestion.expectedCost ? ` / Cost: ${suggestion.expectedCost.toFixed(2)} in ${suggestion.expectedSteps?.toFixed(1)} steps` : '';
+    const tooltipTitle = `Score: ${suggestion.score.toFixed(3)} (IG: ${suggestion.ig?.toFixed(3) ?? 'N/A'} / MaxIG: ${suggestion.max_ig?.toFixed(3) ?? 'N/A'}${plan})`;
 
     return (
       <Tooltip title={tooltipTitle}>
@@ -63,24 +71,30 @@ const ScoreBar = React.memo(({ suggestion }: { suggestion?: TraitSuggestion }) =
     );
 });
 
-const ContinuousInput = ({ trait, selectedValue, onApply }: { trait: Trait, selectedValue: number | undefined, onApply: (val: number | null) => void }) => {
-    const [localValue, setLocalValue] = useState<number | string>(selectedValue ?? "");
+const ContinuousInput = ({ trait, selectedValue, isNA, onApply, lang = "ja" }: { trait: Trait, selectedValue: ContinuousObservation | undefined, isNA: boolean, onApply: (obs: ContinuousObservation | null) => void, lang?: "ja" | "en" }) => {
+    const T = STR[lang].traitsPanel;
+    const [localValue, setLocalValue] = useState<number | string>(selectedValue?.value ?? "");
+    const [localError, setLocalError] = useState<string>(selectedValue?.error ? String(selectedValue.error) : "");
     const min = trait.minValue ?? 0;
     const max = trait.maxValue ?? 100;
     const isInteger = trait.isInteger ?? false;
     const step = isInteger ? 1 : parseFloat(((max - min) / 100).toPrecision(2));
 
-    useEffect(() => { setLocalValue(selectedValue ?? ""); }, [selectedValue]);
+    useEffect(() => {
+        setLocalValue(selectedValue?.value ?? "");
+        setLocalError(selectedValue?.error ? String(selectedValue.error) : "");
+    }, [selectedValue]);
 
     const handleApply = () => {
         let num = typeof localValue === 'string' ? parseFloat(localValue) : localValue;
         if (!isNaN(num)) {
             if (isInteger) num = Math.round(num);
-            onApply(num);
+            const err = Math.abs(parseFloat(localError));
+            onApply(err > 0 ? { value: num, error: err } : { value: num });
         }
     };
 
-    const handleClear = () => { setLocalValue(""); onApply(null); };
+    const handleClear = () => { setLocalValue(""); setLocalError(""); onApply(null); };
 
     return (
         <Stack direction="row" spacing={1.5} alignItems="center" sx={{ width: '100%', maxWidth: 480 }} onClick={(e) => e.stopPropagation()}>
@@ -88,8 +102,10 @@ const ContinuousInput = ({ trait, selectedValue, onApply }: { trait: Trait, sele
             <Slider value={typeof localValue === 'number' ? localValue : min} onChange={(_, v) => setLocalValue(v as number)} min={min} max={max} step={step} valueLabelDisplay="off" />
             <Typography variant="body2" sx={{ minWidth: 40, fontFamily: 'monospace' }}>{max.toFixed(isInteger ? 0 : 1)}</Typography>
             <TextField value={localValue} onChange={(e) => setLocalValue(e.target.value)} size="small" variant="outlined" inputProps={{ step, min, max, type: 'number' }} sx={{ width: 110, minWidth: 110 }} />
+            <TextField value={localError} onChange={(e) => setLocalError(e.target.value)} size="small" variant="outlined" placeholder={T.measurement_error} inputProps={{ step, min: 0, type: 'number' }} sx={{ width: 90, minWidth: 90 }} />
             <Tooltip title="値を設定"><span><IconButton size="small" color="primary" onClick={handleApply}><CheckCircleOutlineIcon /></IconButton></span></Tooltip>
-            <Tooltip title="値をクリア"><span><IconButton size="small" onClick={handleClear} disabled={selectedValue === undefined}><ClearIcon /></IconButton></span></Tooltip>
+            {isNA && <Chip size="small" label={T.state_na} />}
+            <Tooltip title="値をクリア"><span><IconButton size="small" onClick={handleClear} disabled={selectedValue === undefined && !isNA}><ClearIcon /></IconButton></span></Tooltip>
         </Stack>
     );
 };
@@ -153,10 +169,12 @@ const MultiChoiceInlineInput = ({ trait, selectedValues, isNA, onApply, onSetNA,
     );
 };
 
-const RowRenderer = React.memo(({ r, selected, selectedMulti, setBinary, setContinuous, setMulti, setMultiAsNA, setDerivedPick, clearDerived, rank, suggestion, onTraitSelect, lang = "ja" }: {
+const RowRenderer = React.memo(({ r, mode, selected, selectedMulti, selectedContinuous, setBinary, setContinuous, setMulti, setMultiAsNA, setDerivedPick, clearDerived, rank, suggestion, onTraitSelect, confidence, setConfidence, lang = "ja" }: {
   r: TraitRow;
+  mode: Props["mode"];
   selected: Record<string, number>;
   selectedMulti: Record<string, MultiChoice>;
+  selectedContinuous: Record<string, ContinuousObservation>;
   setBinary: Props["setBinary"];
   setContinuous: Props["setContinuous"];
   setMulti: Props["setMulti"];
@@ -166,6 +184,8 @@ const RowRenderer = React.memo(({ r, selected, selectedMulti, setBinary, setCont
   rank?: number;
   suggestion?: TraitSuggestion;
   onTraitSelect: (trait?: Trait) => void;
+  confidence: Props["confidence"];
+  setConfidence: Props["setConfidence"];
   lang?: "ja" | "en";
 }) => {
   const T = STR[lang].traitsPanel;
@@ -175,6 +195,10 @@ const RowRenderer = React.memo(({ r, selected, selectedMulti, setBinary, setCont
   const helpText = lang === 'ja' ? traitObject.helpText_jp : traitObject.helpText_en;
   const hasHelpText = helpText && helpText.trim() !== "";
   const hasHelpImages = traitObject.helpImages && traitObject.helpImages.length > 0 && traitObject.helpImages[0] !== "";
+  // The engine looks up a state group's confidence by the parent's #TraitID.
+  const confidenceKey = r.type === 'derived' ? r.parentTrait.traitId || r.parentTrait.id : traitObject.id;
+  const answeredNA = r.type === 'derived' ? r.children.some(c => selected[c.id] === 0) : selected[traitObject.id] === 0;
+  const hedged = (confidence[confidenceKey] ?? 1) < 1;
 
   return (
     <TableRow hover onClick={() => onTraitSelect(traitObject)} sx={{ cursor: 'pointer' }}>
@@ -184,6 +208,8 @@ const RowRenderer = React.memo(({ r, selected, selectedMulti, setBinary, setCont
       <TableCell>{r.traitName}</TableCell>
       <TableCell><Stack direction="row" spacing={0.5}>{hasHelpText && <DescriptionIcon fontSize="small" color="action" />}{hasHelpImages && <ImageIcon fontSize="small" color="action" />}</Stack></TableCell>
       <TableCell sx={{ py: 0.5 }}>
+        <Stack direction="row" spacing={1} alignItems="center">
+        <Box sx={{ flex: 1 }}>
         {r.type === "binary" ? (
              <ButtonGroup variant="outlined" size="small" onClick={(e) => e.stopPropagation()}>
                 <Button variant={selected[r.binary.id] === 1 ? 'contained' : 'outlined'} onClick={() => setBinary(r.binary.id, 1, r.traitName)}>{T.state_yes}</Button>
@@ -192,7 +218,7 @@ const RowRenderer = React.memo(({ r, selected, selectedMulti, setBinary, setCont
                 <Tooltip title={T.tooltip_clear}><Button onClick={() => setBinary(r.binary.id, null, r.traitName)}>{T.state_clear}</Button></Tooltip>
             </ButtonGroup>
         ) : r.type === "continuous" ? (
-            <ContinuousInput trait={r.continuous} selectedValue={selected[r.continuous.id]} onApply={(val) => setContinuous(r.continuous.id, val, r.traitName)} />
+            <ContinuousInput trait={r.continuous} selectedValue={selectedContinuous[r.continuous.id]} isNA={selected[r.continuous.id] === 0} onApply={(obs) => setContinuous(r.continuous.id, obs, r.traitName)} lang={lang} />
         ) : r.type === "categorical_multi" ? (
             <MultiChoiceInlineInput
                 trait={r.multi}
@@ -210,6 +236,22 @@ const RowRenderer = React.memo(({ r, selected, selectedMulti, setBinary, setCont
                 <Tooltip title={T.tooltip_clear}><Button size="small" variant="outlined" onClick={() => clearDerived(r.children.map(x => x.id), r.traitName, false)}>{T.state_clear}</Button></Tooltip>
             </Stack>
         )}
+        </Box>
+        {mode === "selected" && !answeredNA && (
+            <Tooltip title={T.tooltip_hedge}>
+                <ToggleButtonGroup
+                    size="small"
+                    exclusive
+                    value={hedged ? "probably" : "sure"}
+                    onChange={(_, v) => v && setConfidence(confidenceKey, v === "probably" ? HEDGE_PROBABLY : null)}
+                    onClick={(e) => e.stopPropagation()}
+                >
+                    <ToggleButton value="sure">{T.hedge_sure}</ToggleButton>
+                    <ToggleButton value="probably">{T.hedge_probably}</ToggleButton>
+                </ToggleButtonGroup>
+            </Tooltip>
+        )}
+        </Stack>
       </TableCell>
     </TableRow>
   );
@@ -220,13 +262,22 @@ const RowRenderer = React.memo(({ r, selected, selectedMulti, setBinary, setCont
 // =============================================================================
 
 export default function TraitsPanel(props: Props) {
-  const { mode, rows, selected, selectedMulti, sortBy, suggMap } = props;
+  const { mode, rows, selected, selectedMulti, selectedContinuous, inactive, sortBy, suggMap } = props;
   const { traits: allTraits } = useMatrix();
+  const inactiveSet = useMemo(() => new Set(inactive), [inactive]);
 
   const isTraitVisible = (trait: Trait): boolean => {
+    if (inactiveSet.has(trait.id)) {
+      return false;
+    }
     if (!trait.parentDependency || !allTraits) {
       return true;
     }
+    // Compound conditions (AND/OR, !=, >, <) are evaluated by the engine, which
+    // reports the dependents they rule out in `inactive`.
+    if (!trait.parentDependency.parentTraitId) {
+      return true;
+    }
 
     const parentId = trait.parentDependency.parentTraitId;
     const requiredState = trait.parentDependency.requiredState.toLowerCase();
@@ -273,7 +324,10 @@ export default function TraitsPanel(props: Props) {
       }
       
       if (r.type === 'binary') return mode === 'selected' ? selected[r.binary.id] !== undefined : selected[r.binary.id] === undefined;
-      if (r.type === 'continuous') return mode === 'selected' ? selected[r.continuous.id] !== undefined : selected[r.continuous.id] === undefined;
+      if (r.type === 'continuous') {
+          const isSelected = selectedContinuous[r.continuous.id] !== undefined || selected[r.continuous.id] === 0;
+          return mode === 'selected' ? isSelected : !isSelected;
+      }
       if (r.type === 'categorical_multi') {
           const isSelectedWithValues = props.selectedMulti[r.multi.id] !== undefined && props.selectedMulti[r.multi.id].length > 0;
           const isSelectedAsNA = selected[r.multi.id] === 0;
@@ -297,7 +351,7 @@ export default function TraitsPanel(props: Props) {
         }
         return a.traitName.localeCompare(b.traitName);
       });
-  }, [rows, mode, selected, selectedMulti, sortBy, suggMap, allTraits]); 
+  }, [rows, mode, selected, selectedMulti, selectedContinuous, inactiveSet, sortBy, suggMap, allTraits]); 
 
   const localSuggRank = useMemo(() => {
     if (sortBy !== 'recommend') return {};
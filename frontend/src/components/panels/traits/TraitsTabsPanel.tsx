@@ -1,8 +1,8 @@
 // frontend/src/components/panels/traits/TraitsTabsPanel.tsx
-import React, { useState } from 'react';
-import { Box, Tab, Tabs, ButtonGroup, Button, Stack, Divider, IconButton, Tooltip } from '@mui/material';
+import React, { useCallback, useMemo, useState } from 'react';
+import { Box, Tab, Tabs, ButtonGroup, Button, Stack, Divider, IconButton, Tooltip, FormControl, Select, MenuItem } from '@mui/material';
 import TraitsPanel, { TraitRow } from './TraitsPanel';
-import { Trait, TraitSuggestion, MultiChoice } from '../../../api';
+import { Trait, TraitSuggestion, MultiChoice, ContinuousObservation } from '../../../api';
 import { AlgoOptions } from '../../../hooks/useAlgoOpts';
 import { STR } from '../../../i18n';
 import ReplayIcon from '@mui/icons-material/Replay';
@@ -14,13 +14,16 @@ type Props = {
     rows: TraitRow[];
     selected: Record<string, number>;
     selectedMulti: Record<string, MultiChoice>;
+    selectedContinuous: Record<string, ContinuousObservation>;
+    inactive: string[];
     setBinary: (traitId: string, val: number | null, label: string) => void;
-    setContinuous: (traitId: string, val: number | null, label: string) => void;
+    setContinuous: (traitId: string, obs: ContinuousObservation | null, label: string) => void;
     setMulti: (traitId: string, values: MultiChoice, label: string) => void;
     setMultiAsNA: (traitId: string, label?: string) => void;
     setDerivedPick: (childrenIds: string[], chosenId: string, parentLabel: string) => void;
     clearDerived: (childrenIds: string[], parentLabel?: string, asNA?: boolean) => void;
     clearAllSelections: () => void;
+    setGroupNA: (group: string) => void;
     sortBy: "recommend" | "group" | "name";
     setSortBy: React.Dispatch<React.SetStateAction<"recommend" | "group" | "name">>;
     suggMap: Record<string, TraitSuggestion>;
@@ -36,11 +39,20 @@ type Props = {
 };
 
 export default function TraitsTabsPanel(props: Props) {
-    const { lang, sortBy, setSortBy, selected, clearAllSelections, undo, redo, canUndo, canRedo } = props;
+    const { lang, sortBy, setSortBy, selected, clearAllSelections, setGroupNA, undo, redo, canUndo, canRedo, opts, setOpts } = props;
     const [activeTab, setActiveTab] = useState<"unselected" | "selected">("unselected");
     const T = STR[lang].traitsPanel;
+    const groups = useMemo(() => Array.from(new Set(props.rows.map(r => r.group).filter(g => g))).sort(), [props.rows]);
 
-    const hasSelections = Object.keys(selected).length > 0 || Object.values(props.selectedMulti).some(v => v.length > 0);
+    // A hedged answer writes its confidence to opts.confidence; a sure one removes the entry.
+    const setConfidence = useCallback((traitKey: string, value: number | null) => {
+        setOpts(prev => {
+            const { [traitKey]: _, ...rest } = prev.confidence || {};
+            return { ...prev, confidence: value === null ? rest : { ...rest, [traitKey]: value } };
+        });
+    }, [setOpts]);
+
+    const hasSelections = Object.keys(selected).length > 0 || Object.values(props.selectedMulti).some(v => v.length > 0) || Object.keys(props.selectedContinuous).length > 0;
 
     return (
         <Box sx={{ height: '100%', display: 'flex', flexDirection: 'column' }}>
@@ -63,6 +75,11 @@ export default function TraitsTabsPanel(props: Props) {
                         <Button onClick={() => setSortBy("name")} variant={sortBy === "name" ? "contained" : "outlined"}>{T.sort_name}</Button>
                     </ButtonGroup>
                     <Divider orientation="vertical" flexItem />
+                    <FormControl size="small" sx={{ minWidth: 180 }}>
+                        <Select displayEmpty value="" renderValue={() => T.group_na} onChange={(e) => { if (e.target.value) setGroupNA(e.target.value as string); }}>
+                            {groups.map(g => <MenuItem key={g} value={g}>{g}</MenuItem>)}
+                        </Select>
+                    </FormControl>
                     <Button
                         size="small"
                         variant="outlined"
@@ -77,10 +94,10 @@ export default function TraitsTabsPanel(props: Props) {
             </Stack>
             <Box sx={{ flex: 1, minHeight: 0, p: 2 }}>
                 <Box sx={{ display: activeTab === 'unselected' ? 'block' : 'none', height: '100%' }}>
-                    <TraitsPanel {...props} mode="unselected" />
+                    <TraitsPanel {...props} mode="unselected" confidence={opts.confidence || {}} setConfidence={setConfidence} />
                 </Box>
                 <Box sx={{ display: activeTab === 'selected' ? 'block' : 'none', height: '100%' }}>
-                    <TraitsPanel {...props} mode="selected" />
+                    <TraitsPanel {...props} mode="selected" confidence={opts.confidence || {}} setConfidence={setConfidence} />
                 </Box>
             </Box>
         </Box>
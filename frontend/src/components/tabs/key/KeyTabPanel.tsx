@@ -31,9 +31,9 @@ export const KeyTabPanel: React.FC<KeyTabPanelProps> = ({ matrixState }) => { //
     const {
         matrixInfo,
         taxaCount, rows, traits,
-        selected, selectedMulti, setBinary, setContinuous, setMulti, setMultiAsNA, setDerivedPick, clearDerived, clearAllSelections,
-        scores, suggMap, sortBy, setSortBy,
-        algo,
+        selected, selectedMulti, selectedContinuous, setBinary, setContinuous, setMulti, setMultiAsNA, setDerivedPick, clearDerived, clearAllSelections, setGroupNA,
+        scores, dropped, verdict, inactive, suggMap, sortBy, setSortBy,
+        algo, mode,
         opts, setOpts,
         undo, redo, canUndo, canRedo,
         lang,
@@ -103,6 +103,14 @@ export const KeyTabPanel: React.FC<KeyTabPanelProps> = ({ matrixState }) => { //
             support: s?.support ?? 0,
         }));
     }, [scores]);
+
+    const droppedRows = React.useMemo(() => {
+        return (dropped || []).map((s: any) => ({
+            taxon: s.taxon,
+            post: s?.post ?? 0,
+            conflicts: s?.conflicts ?? 0,
+        }));
+    }, [dropped]);
     
     React.useEffect(() => {
         if (scores && scores.length > 0) {
@@ -127,7 +135,7 @@ export const KeyTabPanel: React.FC<KeyTabPanelProps> = ({ matrixState }) => { //
                     <ResizerY onMouseDown={createVerticalResizer(setLeftTopPanelHeight)} />
                     
                     <Paper elevation={2} sx={{ flex: 1, minHeight: '100px', display: 'flex', flexDirection: 'column', overflow: 'hidden' }}>
-                        {detailView?.type === 'taxon' && <TaxonDetailPanel taxon={detailView.content} lang={lang} />}
+                        {detailView?.type === 'taxon' && <TaxonDetailPanel taxon={detailView.content} lang={lang} traits={traits} />}
                         {detailView?.type === 'trait' && <HelpDisplay trait={detailView.content} lang={lang} />}
                         {!detailView && (
                             <Box sx={{height: '100%', width: '100%', p:2, display: 'flex', alignItems: 'center', justifyContent: 'center'}}>
@@ -153,6 +161,11 @@ export const KeyTabPanel: React.FC<KeyTabPanelProps> = ({ matrixState }) => { //
                             onTaxonSelect={(taxon) => setDetailView({type: 'taxon', content: taxon})}
                             selected={selected}
                             selectedMulti={selectedMulti}
+                            selectedContinuous={selectedContinuous}
+                            opts={opts}
+                            verdict={verdict}
+                            dropped={droppedRows}
+                            mode={mode}
                         />
                     </Paper>
                     
@@ -164,6 +177,8 @@ export const KeyTabPanel: React.FC<KeyTabPanelProps> = ({ matrixState }) => { //
                             rows={rows}
                             selected={selected as Record<string, number>}
                             selectedMulti={selectedMulti}
+                            selectedContinuous={selectedContinuous}
+                            inactive={inactive}
                             setBinary={setBinary}
                             setContinuous={setContinuous}
                             setMulti={setMulti}
@@ -171,6 +186,7 @@ export const KeyTabPanel: React.FC<KeyTabPanelProps> = ({ matrixState }) => { //
                             setDerivedPick={setDerivedPick}
                             clearDerived={clearDerived}
                             clearAllSelections={clearAllSelections}
+                            setGroupNA={setGroupNA}
                             sortBy={sortBy}
                             setSortBy={setSortBy}
                             suggMap={suggMap}
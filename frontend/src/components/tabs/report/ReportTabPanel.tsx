@@ -5,7 +5,7 @@ import SaveIcon from '@mui/icons-material/Save';
 import ContentCopyIcon from '@mui/icons-material/ContentCopy';
 import { SaveReport } from '../../../../wailsjs/go/main/App';
 import { STR } from '../../../i18n';
-import { TaxonScore, Taxon } from '../../../api';
+import { TaxonScore, Taxon, StopVerdict } from '../../../api';
 import { UseMatrixReturn } from '../../../hooks/useMatrix';
 import { RichTextEditor } from '../../common/RichTextEditor'; // ★ RichTextEditorをインポート
 
@@ -21,10 +21,21 @@ const formatTaxonNameForReport = (taxon: Taxon): string => {
     return taxon.name || taxon.id;
 };
 
+// 停止判定の信頼度を、レポートの文言に変換する
+const confidenceWording = (verdict: StopVerdict, s: (typeof STR)['ja' | 'en']['report']): string => {
+    switch (verdict.confidence) {
+        case 'absolute': return s.confidenceAbsolute;
+        case 'very_high': return s.confidenceVeryHigh;
+        case 'high': return s.confidenceHigh.replace('{ratio}', verdict.ratio.toFixed(2));
+        case 'contested': return verdict.reason === 'no_informative' ? s.confidenceNoInformative : s.confidenceContested;
+        default: return s.confidenceNA;
+    }
+};
+
 // レポートをHTMLとして生成する関数
 const generateReportHtml = (matrixState: UseMatrixReturn, lang: 'ja' | 'en'): string => {
     const s = STR[lang].report;
-    const { matrixName, algo, opts, history, scores, matrixInfo } = matrixState;
+    const { matrixName, algo, opts, history, scores, matrixInfo, unknownPost, hierarchy, verdict } = matrixState;
 
     if (!matrixInfo) {
         return lang === 'ja' ? "<p>マトリクスが読み込まれていません。</p>" : "<p>No matrix is loaded.</p>";
@@ -49,6 +60,10 @@ const generateReportHtml = (matrixState: UseMatrixReturn, lang: 'ja' | 'en'): st
         sb += `<p>- <b>${s.conflictPenalty}:</b> ${opts.conflictPenalty.toFixed(2)}</p>`;
         sb += `<p>- <b>${s.gammaNAPenalty}:</b> ${opts.gammaNAPenalty.toFixed(2)}</p>`;
         sb += `<p>- <b>${s.kappa}:</b> ${opts.kappa.toFixed(2)}</p>`;
+        sb += `<p>- <b>${s.lambda}:</b> ${(opts.lambda ?? 1).toFixed(2)}</p>`;
+        if (opts.useVerifiedCases) {
+            sb += `<p>- <b>${s.errorPrior}:</b> ${(opts.priorStrength || 2).toFixed(1)}</p>`;
+        }
     }
     sb += `<p>- <b>${s.tolerance}:</b> ${(opts.toleranceFactor * 100).toFixed(0)}%</p>`;
 
@@ -77,6 +92,25 @@ const generateReportHtml = (matrixState: UseMatrixReturn, lang: 'ja' | 'en'): st
             sb += `<p>...and ${scores.length - 10} more.</p>`;
         }
     }
+
+    sb += hr + `<p><b>${s.confidenceTitle}</b></p>`;
+    if (verdict) {
+        sb += `<p>- <b>${s.confidenceLevel}:</b> ${confidenceWording(verdict, s)}</p>`;
+        if (verdict.setSize > 0) {
+            sb += `<p>- <b>${s.credibleSet.replace('{level}', `${(verdict.level * 100).toFixed(0)}%`)}:</b> ${verdict.setSize}</p>`;
+        }
+    }
+    if (algo === 'bayes' && opts.unknownPrior > 0) {
+        sb += `<p>- <b>${s.unknownTaxon}:</b> ${(unknownPost * 100).toFixed(2)}%</p>`;
+    }
+    const threshold = `${((opts.rankThreshold || 0.95) * 100).toFixed(0)}%`;
+    const confident = hierarchy?.confident;
+    if (confident) {
+        sb += `<p>- <b>${s.confidentAtRank.replace('{threshold}', threshold)}:</b> ${confident.rank} <i>${confident.name}</i> (${(confident.post * 100).toFixed(2)}%)</p>`;
+    } else if (scores.length > 0) {
+        sb += `<p>- ${s.noConfidentRank.replace('{threshold}', threshold)}</p>`;
+    }
+    sb += `<p>${s.confidenceDisclaimer}</p>`;
     
     return sb;
 };
@@ -2,19 +2,29 @@
 import { useEffect, useState } from "react";
 import { main } from "../../wailsjs/go/models";
 
-const SETTINGS_VERSION = 6;
+const SETTINGS_VERSION = 7;
 
 export type AlgoOptions = main.ApplyOptions & {
   settingsVersion?: number;
   gammaNAPenalty: number;
   epsilonCut:     number;
   conflictPenalty: number;
+  tolerateConflicts: boolean; // Use the "tolerant" mode instead of deriving strict/lenient from conflictPenalty
+  maxConflicts: number; // Conflicts a taxon may have in the "tolerant" mode
   usePragmaticScore: boolean;
-  recommendationStrategy: "expected_ig" | "max_ig";
+  recommendationStrategy: "expected_ig" | "max_ig" | "plan";
+  planDepth: number; // Observations the "plan" strategy looks ahead
   applyDependencies: boolean; // NEW
   toleranceFactor: number;
+  continuousAlgo: "range" | "gaussian" | "truncated_normal"; // Likelihood model for continuous traits
+  rangeCoverage: number; // Quantile interval a min-max range stands for under the normal models
   categoricalAlgo: "jaccard" | "binary";
   jaccardThreshold: number;
+  unknownPrior: number; // Prior of the "taxon not in this key" class; 0 = off
+  rankThreshold: number; // Probability a taxonomic group needs to be reported as confident
+  credibleLevel: number; // Mass the credible set of taxa must cover
+  stopRatio: number; // Top-to-second ratio needed before the verdict says "stop"
+  minInfoGain: number; // Expected IG (bits) below which no trait is worth asking
 };
 
 export const DEFAULT_OPTS: AlgoOptions = {
@@ -25,16 +35,26 @@ export const DEFAULT_OPTS: AlgoOptions = {
   kappa:          1.0,
   epsilonCut:     1e-6,
   conflictPenalty: 0.5,
+  tolerateConflicts: false,
+  maxConflicts: 1,
   usePragmaticScore: true,
   recommendationStrategy: "max_ig",
+  planDepth: 2,
   applyDependencies: true, // NEW
   toleranceFactor: 0.1,
+  continuousAlgo: "range",
+  rangeCoverage: 0.95,
   categoricalAlgo: "binary", 
   jaccardThreshold: 0.01, 
   wantInfoGain: false,
   lambda: 1.0,
-  a0: 1.0,
-  b0: 1.0,
+  priorStrength: 2,
+  useVerifiedCases: false,
+  unknownPrior: 0,
+  rankThreshold: 0.95,
+  credibleLevel: 0.95,
+  stopRatio: 10,
+  minInfoGain: 0.01,
   alphaFP: {},
   betaFN: {},
   confidence: {},
@@ -50,7 +70,8 @@ export function useAlgoOpts(matrixName: string) {
       if (raw) {
         const parsed = JSON.parse(raw);
         if (!parsed.settingsVersion || parsed.settingsVersion < SETTINGS_VERSION) {
-          const migratedOpts = { ...DEFAULT_OPTS, ...parsed, settingsVersion: SETTINGS_VERSION };
+          const { a0, b0, ...rest } = parsed; // a0/b0 were replaced by priorStrength in version 7
+          const migratedOpts = { ...DEFAULT_OPTS, ...rest, settingsVersion: SETTINGS_VERSION };
           return migratedOpts;
         }
         return { ...DEFAULT_OPTS, ...parsed };
@@ -77,7 +98,17 @@ export function clampAlgoOptions(o: AlgoOptions): AlgoOptions {
     kappa:          clamp(o.kappa,          0, 5),
     epsilonCut:     clamp(o.epsilonCut,     1e-12, 1e-3),
     conflictPenalty: clamp(o.conflictPenalty, 0, 1),
+    maxConflicts: Math.round(clamp(o.maxConflicts ?? 1, 0, 20)),
     toleranceFactor: clamp(o.toleranceFactor, 0, 0.5),
+    rangeCoverage: clamp(o.rangeCoverage ?? 0.95, 0.5, 0.999),
     jaccardThreshold: clamp(o.jaccardThreshold, 0, 1),
+    unknownPrior: clamp(o.unknownPrior ?? 0, 0, 0.5),
+    rankThreshold: clamp(o.rankThreshold ?? 0.95, 0.5, 1),
+    credibleLevel: clamp(o.credibleLevel ?? 0.95, 0.5, 1),
+    stopRatio: clamp(o.stopRatio ?? 10, 1, 1000),
+    minInfoGain: clamp(o.minInfoGain ?? 0.01, 0, 1),
+    planDepth: clamp(o.planDepth ?? 2, 1, 3),
+    lambda: clamp(o.lambda ?? 1, 0, 3),
+    priorStrength: clamp(o.priorStrength ?? 2, 0, 100),
   };
 }
\ No newline at end of file
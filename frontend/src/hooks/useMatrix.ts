@@ -1,8 +1,8 @@
 // frontend/src/hooks/useMatrix.ts
 import { useCallback, useEffect, useMemo, useRef, useState, Dispatch, SetStateAction } from "react";
 import { EnsureMyKeysAndSamples, ListMyKeys, GetCurrentKeyName, PickKey } from "../../wailsjs/go/main/App";
-import { applyFilters } from "../utils/applyFilters";
-import { Matrix, TaxonScore, Trait, TraitSuggestion, Choice, MultiChoice, HistoryItem, MatrixInfo } from "../api";
+import { applyFilters, ApplyResult, EvalMode } from "../utils/applyFilters";
+import { Matrix, TaxonScore, Trait, TraitSuggestion, Choice, MultiChoice, ContinuousObservation, HistoryItem, MatrixInfo, Hierarchy, StopVerdict } from "../api";
 import { EventsOn } from "../../wailsjs/runtime/runtime";
 import { useAlgoOpts, AlgoOptions } from "./useAlgoOpts";
 import { TraitRow } from "../components/panels/traits/TraitsPanel";
@@ -12,6 +12,7 @@ export type KeyInfo = { name: string; path?: string };
 type HistoryState = {
   selected: Record<string, Choice>;
   selectedMulti: Record<string, MultiChoice>;
+  selectedContinuous: Record<string, ContinuousObservation>;
   log: HistoryItem;
 };
 
@@ -24,20 +25,27 @@ export type UseMatrixReturn = {
   taxaCount: number;
   selected: Record<string, Choice>;
   selectedMulti: Record<string, MultiChoice>;
+  selectedContinuous: Record<string, ContinuousObservation>;
   setBinary: (traitId: string, val: Choice | null, label?: string) => void;
-  setContinuous: (traitId: string, val: number | null, label?: string) => void;
+  setContinuous: (traitId: string, obs: ContinuousObservation | null, label?: string) => void;
   setMulti: (traitId: string, values: MultiChoice, label?: string) => void;
   setMultiAsNA: (traitId: string, label?: string) => void;
   setDerivedPick: (childrenIds: string[], chosenId: string, parentLabel?: string) => void;
   clearDerived: (childrenIds: string[], parentLabel?: string, asNA?: boolean) => void;
   clearAllSelections: () => void;
-  mode: "strict" | "lenient";
-  setMode: (newMode: "strict" | "lenient") => void;
-  algo: "bayes" | "heuristic";
-  setAlgo: Dispatch<SetStateAction<"bayes" | "heuristic">>;
+  setGroupNA: (group: string) => void;
+  mode: EvalMode;
+  setMode: (newMode: EvalMode) => void;
+  algo: string; // Name of a registered scorer (ListAlgorithms)
+  setAlgo: Dispatch<SetStateAction<string>>;
   opts: AlgoOptions;
   setOpts: Dispatch<SetStateAction<AlgoOptions>>;
   scores: TaxonScore[];
+  dropped: TaxonScore[]; // Taxa eliminated by the strict/tolerant mode
+  unknownPost: number; // Posterior of "taxon not in this key" (0 when disabled)
+  hierarchy: Hierarchy | null; // Probability summed by taxonomic rank
+  verdict: StopVerdict | null; // Whether the identification can stop
+  inactive: string[]; // Trait IDs ruled out by the #Dependency of an observed parent
   suggs: TraitSuggestion[];
   suggMap: Record<string, TraitSuggestion>;
   sortBy: "recommend" | "group" | "name";
@@ -87,16 +95,21 @@ export function useMatrix(): UseMatrixReturn {
   const [historyIndex, setHistoryIndex] = useState<number>(-1);
   
   const { opts, setOpts } = useAlgoOpts(matrixName);
-  const [algo, setAlgo] = useState<"bayes" | "heuristic">("bayes");
-  const mode = useMemo(() => (opts.conflictPenalty > 0.5 ? "strict" : "lenient"), [opts.conflictPenalty]);
+  const [algo, setAlgo] = useState<string>("bayes");
+  const mode = useMemo<EvalMode>(() => (opts.tolerateConflicts ? "tolerant" : opts.conflictPenalty > 0.5 ? "strict" : "lenient"), [opts.tolerateConflicts, opts.conflictPenalty]);
   
   const [scores, setScores] = useState<TaxonScore[]>([]);
+  const [dropped, setDropped] = useState<TaxonScore[]>([]);
+  const [unknownPost, setUnknownPost] = useState(0);
+  const [hierarchy, setHierarchy] = useState<Hierarchy | null>(null);
+  const [verdict, setVerdict] = useState<StopVerdict | null>(null);
+  const [inactive, setInactive] = useState<string[]>([]);
   const [suggs, setSuggs] = useState<TraitSuggestion[]>([]);
   const [suggAlgo, setSuggAlgo] = useState<"gini" | "entropy">("gini");
   const [sortBy, setSortBy] = useState<"recommend" | "group" | "name">("recommend");
 
-  const currentState = history[historyIndex] ?? { selected: {}, selectedMulti: {}, log: { traitName: "Initial State", selection: "", timestamp: 0 } };
-  const { selected, selectedMulti } = currentState;
+  const currentState = history[historyIndex] ?? { selected: {}, selectedMulti: {}, selectedContinuous: {}, log: { traitName: "Initial State", selection: "", timestamp: 0 } };
+  const { selected, selectedMulti, selectedContinuous } = currentState;
 
   const currentHistoryLogs = history.slice(0, historyIndex + 1).map((h) => h.log).filter((log) => log.traitName !== "Initial State");
 
@@ -108,8 +121,12 @@ export function useMatrix(): UseMatrixReturn {
     }
   }, [lang]);
 
-  const setMode = useCallback((newMode: "strict" | "lenient") => {
-    setOpts((prev) => ({ ...prev, conflictPenalty: newMode === "strict" ? 1.0 : 0.0 }));
+  const setMode = useCallback((newMode: EvalMode) => {
+    if (newMode === "tolerant") {
+      setOpts((prev) => ({ ...prev, tolerateConflicts: true }));
+      return;
+    }
+    setOpts((prev) => ({ ...prev, tolerateConflicts: false, conflictPenalty: newMode === "strict" ? 1.0 : 0.0 }));
   }, [setOpts]);
 
   const refreshKeys = useCallback(async () => {
@@ -122,8 +139,8 @@ export function useMatrix(): UseMatrixReturn {
     }
   }, []);
 
-  const pushHistory = useCallback((newSelected: Record<string, Choice>, newSelectedMulti: Record<string, MultiChoice>, log: HistoryItem) => {
-    const newState: HistoryState = { selected: newSelected, selectedMulti: newSelectedMulti, log };
+  const pushHistory = useCallback((newSelected: Record<string, Choice>, newSelectedMulti: Record<string, MultiChoice>, newSelectedContinuous: Record<string, ContinuousObservation>, log: HistoryItem) => {
+    const newState: HistoryState = { selected: newSelected, selectedMulti: newSelectedMulti, selectedContinuous: newSelectedContinuous, log };
     setHistory((prev) => {
       const base = prev.slice(0, historyIndex + 1);
       const next = [...base, newState];
@@ -144,9 +161,10 @@ export function useMatrix(): UseMatrixReturn {
         setTraits([]);
         setTaxaCount(0);
         setMatrixName("");
-        setHistory([{ selected: {}, selectedMulti: {}, log: { traitName: "Initial State", selection: "", timestamp: Date.now() } }]);
+        setHistory([{ selected: {}, selectedMulti: {}, selectedContinuous: {}, log: { traitName: "Initial State", selection: "", timestamp: Date.now() } }]);
         setHistoryIndex(0);
         setScores([]);
+        setDropped([]);
         setSuggs([]);
       }
     } catch (error) {
@@ -168,9 +186,10 @@ export function useMatrix(): UseMatrixReturn {
       setMatrixName(m.name ?? "");
       setActiveKey((prev) => prev ?? m.name);
       
-      setHistory([{ selected: {}, selectedMulti: {}, log: { traitName: "Initial State", selection: "", timestamp: Date.now() } }]);
+      setHistory([{ selected: {}, selectedMulti: {}, selectedContinuous: {}, log: { traitName: "Initial State", selection: "", timestamp: Date.now() } }]);
       setHistoryIndex(0);
       setScores([]);
+      setDropped([]);
       setSuggs([]);
       lastEvaluatedState.current = null;
     } catch (err) {
@@ -199,13 +218,19 @@ export function useMatrix(): UseMatrixReturn {
   const evalTimerRef = useRef<number | undefined>(undefined);
 
   useEffect(() => {
-    const currentStateKey = JSON.stringify({ selected, selectedMulti, mode, algo, opts: { conflictPenalty: opts.conflictPenalty, applyDependencies: opts.applyDependencies } });
+    const currentStateKey = JSON.stringify({ selected, selectedMulti, selectedContinuous, mode, algo, opts: { conflictPenalty: opts.conflictPenalty, maxConflicts: opts.maxConflicts, defaultAlphaFP: opts.defaultAlphaFP, defaultBetaFN: opts.defaultBetaFN, gammaNAPenalty: opts.gammaNAPenalty, kappa: opts.kappa, lambda: opts.lambda, priorStrength: opts.priorStrength, useVerifiedCases: opts.useVerifiedCases, continuousAlgo: opts.continuousAlgo, rangeCoverage: opts.rangeCoverage, applyDependencies: opts.applyDependencies, unknownPrior: opts.unknownPrior, rankThreshold: opts.rankThreshold, credibleLevel: opts.credibleLevel, stopRatio: opts.stopRatio, minInfoGain: opts.minInfoGain, recommendationStrategy: opts.recommendationStrategy, planDepth: opts.planDepth, confidence: opts.confidence } });
     if (currentStateKey !== lastEvaluatedState.current) {
       if (evalTimerRef.current) window.clearTimeout(evalTimerRef.current);
       evalTimerRef.current = window.setTimeout(() => {
-        applyFilters(selected, selectedMulti, mode, algo, { ...opts, wantInfoGain: true })
+        applyFilters(selected, selectedMulti, selectedContinuous, mode, algo, { ...opts, wantInfoGain: true })
           .then((res) => {
             setScores(res.scores || []);
+            setDropped(res.dropped || []);
+            const extra = res as ApplyResult & { unknown?: number; hierarchy?: Hierarchy; verdict?: StopVerdict; inactive?: string[] };
+            setUnknownPost(extra.unknown ?? 0);
+            setHierarchy(extra.hierarchy ?? null);
+            setVerdict(extra.verdict ?? null);
+            setInactive(extra.inactive ?? []);
             setSuggs(res.suggestions || []);
             lastEvaluatedState.current = currentStateKey;
           })
@@ -213,7 +238,7 @@ export function useMatrix(): UseMatrixReturn {
       }, 150);
       return () => { if (evalTimerRef.current) window.clearTimeout(evalTimerRef.current); };
     }
-  }, [selected, selectedMulti, mode, algo, opts]);
+  }, [selected, selectedMulti, selectedContinuous, mode, algo, opts]);
 
   const createLog = (traitName: string, selection: string): HistoryItem => ({ traitName, selection, timestamp: Date.now() });
   
@@ -221,37 +246,40 @@ export function useMatrix(): UseMatrixReturn {
       const next = { ...selected };
       if (val === null) delete next[traitId]; else next[traitId] = val;
       const valText = val === null ? "Cleared" : val === 0 ? "NA" : val === 1 ? "Yes" : "No";
-      pushHistory(next, selectedMulti, createLog(label || traitId, valText));
-  }, [selected, selectedMulti, pushHistory]);
+      pushHistory(next, selectedMulti, selectedContinuous, createLog(label || traitId, valText));
+  }, [selected, selectedMulti, selectedContinuous, pushHistory]);
 
-  const setContinuous = useCallback((traitId: string, val: number | null, label?: string) => {
-      const next = { ...selected };
-      if (val === null) delete next[traitId]; else next[traitId] = val;
-      pushHistory(next, selectedMulti, createLog(label || traitId, val === null ? "Cleared" : `${val}`));
-  }, [selected, selectedMulti, pushHistory]);
+  const setContinuous = useCallback((traitId: string, obs: ContinuousObservation | null, label?: string) => {
+      const nextSel = { ...selected };
+      delete nextSel[traitId];
+      const next = { ...selectedContinuous };
+      if (obs === null) delete next[traitId]; else next[traitId] = obs;
+      const valText = obs === null ? "Cleared" : obs.error ? `${obs.value} ± ${obs.error}` : `${obs.value}`;
+      pushHistory(nextSel, selectedMulti, next, createLog(label || traitId, valText));
+  }, [selected, selectedMulti, selectedContinuous, pushHistory]);
 
   const setMulti = useCallback((traitId: string, values: MultiChoice, label?: string) => {
       const nextSel = { ...selected };
       delete nextSel[traitId];
       const nextMulti = { ...selectedMulti };
       if (values.length === 0) delete nextMulti[traitId]; else nextMulti[traitId] = values;
-      pushHistory(nextSel, nextMulti, createLog(label || traitId, `[${values.join(", ")}]`));
-  }, [selected, selectedMulti, pushHistory]);
+      pushHistory(nextSel, nextMulti, selectedContinuous, createLog(label || traitId, `[${values.join(", ")}]`));
+  }, [selected, selectedMulti, selectedContinuous, pushHistory]);
   
   const setMultiAsNA = useCallback((traitId: string, label?: string) => {
     const nextMulti = { ...selectedMulti };
     delete nextMulti[traitId];
     const nextSel = { ...selected, [traitId]: 0 };
-    pushHistory(nextSel, nextMulti, createLog(label || traitId, "NA"));
-  },[selected, selectedMulti, pushHistory]);
+    pushHistory(nextSel, nextMulti, selectedContinuous, createLog(label || traitId, "NA"));
+  },[selected, selectedMulti, selectedContinuous, pushHistory]);
 
   const setDerivedPick = useCallback((childrenIds: string[], chosenId: string, parentLabel?: string) => {
       const next = { ...selected };
       for (const cid of childrenIds) next[cid] = cid === chosenId ? 1 : -1;
       const chosenTrait = traits.find((t) => t.id === chosenId);
       const label = lang === 'ja' ? chosenTrait?.name_jp || chosenTrait?.name_en : chosenTrait?.name_en || chosenTrait?.name_jp;
-      pushHistory(next, selectedMulti, createLog(parentLabel || "(derived)", chosenTrait?.state || label || chosenId));
-  }, [selected, selectedMulti, traits, pushHistory, lang]);
+      pushHistory(next, selectedMulti, selectedContinuous, createLog(parentLabel || "(derived)", chosenTrait?.state || label || chosenId));
+  }, [selected, selectedMulti, selectedContinuous, traits, pushHistory, lang]);
 
   const clearDerived = useCallback((childrenIds: string[], parentLabel?: string, asNA?: boolean) => {
       const next = { ...selected };
@@ -259,12 +287,13 @@ export function useMatrix(): UseMatrixReturn {
         if (asNA) next[cid] = 0;
         else delete next[cid];
       }
-      pushHistory(next, selectedMulti, createLog(parentLabel || "(derived)", asNA ? "NA" : "Cleared"));
-  }, [selected, selectedMulti, pushHistory]);
+      pushHistory(next, selectedMulti, selectedContinuous, createLog(parentLabel || "(derived)", asNA ? "NA" : "Cleared"));
+  }, [selected, selectedMulti, selectedContinuous, pushHistory]);
 
   const clearAllSelections = useCallback(() => {
-    pushHistory({}, {}, createLog("All Selections", "Cleared"));
-  }, [pushHistory]);
+    pushHistory({}, {}, {}, createLog("All Selections", "Cleared"));
+    setOpts((prev) => ({ ...prev, confidence: {} })); // Hedges belong to the cleared answers
+  }, [pushHistory, setOpts]);
 
   const canUndo = historyIndex > 0;
   const canRedo = historyIndex < history.length - 1;
@@ -312,16 +341,34 @@ export function useMatrix(): UseMatrixReturn {
     return out;
   }, [traits, lang]);
 
+  // Marks every trait of a group as NA (unobservable) in one step, e.g. for a
+  // specimen whose wings are missing.
+  const setGroupNA = useCallback((group: string) => {
+      const nextSel = { ...selected };
+      const nextMulti = { ...selectedMulti };
+      const nextCont = { ...selectedContinuous };
+      for (const r of rows) {
+        if (r.group !== group) continue;
+        const ids = r.type === "binary" ? [r.binary.id] : r.type === "continuous" ? [r.continuous.id] : r.type === "categorical_multi" ? [r.multi.id] : r.children.map((c) => c.id);
+        for (const id of ids) {
+          nextSel[id] = 0;
+          delete nextMulti[id];
+          delete nextCont[id];
+        }
+      }
+      pushHistory(nextSel, nextMulti, nextCont, createLog(group, "NA"));
+  }, [rows, selected, selectedMulti, selectedContinuous, pushHistory]);
+
   return useMemo(() => ({
     matrixInfo, setMatrixInfo,
     rows, traits, matrixName, taxaCount,
-    selected, selectedMulti,
+    selected, selectedMulti, selectedContinuous,
     setBinary, setContinuous, setMulti, setMultiAsNA,
-    setDerivedPick, clearDerived, clearAllSelections,
+    setDerivedPick, clearDerived, clearAllSelections, setGroupNA,
     mode, setMode,
     algo, setAlgo,
     opts, setOpts,
-    scores, suggs, suggMap,
+    scores, dropped, unknownPost, hierarchy, verdict, inactive, suggs, suggMap,
     sortBy, setSortBy,
     suggAlgo, setSuggAlgo,
     pickKey, keys, activeKey, refreshKeys,
@@ -330,13 +377,13 @@ export function useMatrix(): UseMatrixReturn {
     lang, setLang,
   }), [
     matrixInfo, rows, traits, matrixName, taxaCount,
-    selected, selectedMulti,
+    selected, selectedMulti, selectedContinuous,
     setBinary, setContinuous, setMulti, setMultiAsNA,
-    setDerivedPick, clearDerived, clearAllSelections,
+    setDerivedPick, clearDerived, clearAllSelections, setGroupNA,
     mode, setMode,
     algo, setAlgo,
     opts, setOpts,
-    scores, suggs, suggMap,
+    scores, dropped, unknownPost, hierarchy, verdict, inactive, suggs, suggMap,
     sortBy, setSortBy,
     suggAlgo, setSuggAlgo,
     pickKey, keys, activeKey, refreshKeys,
@@ -349,4 +396,4 @@ export function useMatrix(): UseMatrixReturn {
     undo, redo, canUndo, canRedo,
     lang, setLang,
   ]);
-}
\ No newline at end of file
+}
\ No newline at end of file
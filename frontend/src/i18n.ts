@@ -46,12 +46,18 @@ export const STR = {
         select_all: "すべて選択",
         deselect_all: "選択をすべて解除",
         show_match_support: "Match/Supportを表示",
+        verdict_stop: "同定完了",
+        verdict_continue: "観察を継続",
+        verdict_no_informative: "これ以上有効な形質なし",
+        verdict_tooltip: "{level}信用集合: {size}分類群 / 1位÷2位: {ratio} / 最大情報利得: {ig} bits",
+        eliminated: "矛盾により除外された候補 ({n})",
     },
     comparisonPanel: {
         title: "タクサ比較",
         select_prompt: "候補リストから2つ以上のタクサを選択して比較します。",
         hide_same_traits: "違いのない形質を隠す",
         trait: "形質",
+        state_variable: "可変 (±)",
     },
     traitsPanel: {
         sort_recommend: "推奨順",
@@ -66,18 +72,58 @@ export const STR = {
         multi_select_tooltip: "複数選択が可能です",
         tooltip_na: "標本の破損などで形質が『観測不能』な場合に使います。この形質は計算から除外されます。",
         tooltip_clear: "この形質に対する選択を解除します。",
+        measurement_error: "± 誤差",
+        group_na: "グループを観察不能にする",
+        hedge_sure: "確実",
+        hedge_probably: "たぶん",
+        tooltip_hedge: "「たぶん」の回答は確実な回答より弱い証拠 (70%) として計算します。",
     },
     justificationPanel: {
         title_prefix: "Justification for:",
         matches: "一致",
         conflicts: "矛盾",
         unobserved: "未観察",
+        unobservable: "観察不能",
+        inapplicable: "依存関係により非該当",
         header_trait: "形質",
         header_your_choice: "あなたの選択",
         header_taxon_data: "タクソンのデータ",
         none: "なし",
         no_data: "データがありません。",
     },
+    verifiedCases: {
+        title: "確認済みの同定事例",
+        record_prompt: "現在の観察結果を次の分類群の確定事例として記録:",
+        note: "メモ",
+        record: "記録",
+        recorded: "記録しました",
+        cases_title: "記録済みの事例",
+        header_taxon: "分類群 (#TaxonID)",
+        header_recorded: "記録日時",
+        header_trait: "形質",
+        delete: "削除",
+        suggestions_title: "誤り率の推奨値",
+        suggestions_description: "記録済みの事例から推定した値です (現在値 → 推奨値、下段は誤答数/回答数)。マトリクスの #AlphaFP / #BetaFN / #Risk 列を見直す際の参考にしてください。",
+        none: "なし",
+        close: "閉じる",
+        open_button: "確認済み事例",
+        record_tooltip: "この分類群で確定として記録",
+    },
+    ensemble: {
+        title: "アルゴリズム間の比較",
+        description: "登録されているすべてのアルゴリズムで同じ観察結果を評価し、順位を並べて表示します。総合順位は平均順位の順です。上位の分類群でアルゴリズム間の順位が大きく異なる場合は警告が付きます。",
+        open_button: "アルゴリズム比較",
+        header_consensus: "総合",
+        header_name: "名前",
+        header_mean_rank: "平均順位",
+        header_spread: "順位差",
+        tooltip_consensus: "平均順位に基づく総合順位",
+        tooltip_spread: "最も低い順位と最も高い順位の差",
+        disagreement_count: "順位が食い違う分類群: {n}",
+        only_disagreements: "食い違いのみ表示",
+        none: "なし",
+        close: "閉じる",
+    },
     candidatesTab: {
       algorithm: "アルゴリズム",
       effect_label: "結果への影響",
@@ -95,6 +141,11 @@ export const STR = {
               { setting: "Lenient (低めの値)", pro: "間違いに頑健", con: "候補が絞り込まれにくい" }
           ]
       },
+      param_tolerant: {
+          name: "一定数までの矛盾を許容 (Tolerant)",
+          max: "許容する矛盾の数",
+          description: "有効にすると、矛盾の数がこの値を超える候補だけを除外します。除外された候補は候補リストの下に表示されます。",
+      },
       param_na: {
           title: "データ欠損(NA)の扱い",
           name: "γ (NAペナルティ) (既定値: 0.8)",
@@ -126,6 +177,7 @@ export const STR = {
         description: "(既定値: 0.07) 「本当はYesなのにNoと観察してしまう」観察エラーの確率。",
       },
       reset_defaults: "既定に戻す",
+      advanced: "詳細設定…",
     },
     traitsTab: {
         param_recommend: {
@@ -136,6 +188,7 @@ export const STR = {
             options: {
                 stable: "安定進行（初心者向け）",
                 breakthrough: "一点突破（専門家向け）",
+                plan: "先読み計画（コスト重視）",
             },
             table_header_strategy: "戦略",
             table_header_merit: "メリット",
@@ -143,8 +196,13 @@ export const STR = {
             tradeoffs: [
               { setting: "一点突破", pro: "専門家が仮説を検証する際に、決定的形質を素早く見つけられる", con: "ほとんどの場合で情報量の少ない形質が上位に来る可能性がある" },
               { setting: "安定進行", pro: "初心者でも迷いにくく、着実に同定を進められる", con: "一発逆転の「キラー形質」が見逃されやすい" },
+              { setting: "先読み計画", pro: "数手先まで読み、同定完了までの観察の手間（難易度の合計）が最小になる形質を選ぶ", con: "計算に時間がかかり、大きなマトリクスでは推薦の更新が遅くなる" },
             ]
         },
+        plan_depth: {
+            name: "先読みの深さ (既定値: 2)",
+            description: "何回先の観察まで探索するか。深いほど正確ですが、計算時間が大きく増えます。",
+        },
         pragmatic_score: {
             name: "実用性スコアを有効化 (既定値: 有効)",
             description: "有効にすると、単なる情報量だけでなく、「観察のしやすさ(コスト)」と「見間違いにくさ(リスク)」を考慮して次に調べるべき形質を推薦します。",
@@ -161,6 +219,19 @@ export const STR = {
             name: "許容範囲 (Tolerance) (既定値: 10%)",
             description: "連続値（長さなど）のデータ範囲に対して、ユーザーの入力値がどの程度範囲外でも「一致」と見なすかを設定します。",
         },
+        continuous_algo: {
+            name: "尤度モデル (既定値: 範囲)",
+            description: "「範囲」は範囲内を一致、範囲外を不一致として扱います。正規分布モデルは最小–最大の範囲を分布の区間とみなし、範囲の中心から離れるほど尤度を下げます。",
+            options: {
+                range: "範囲",
+                gaussian: "正規分布",
+                truncated_normal: "切断正規分布",
+            },
+        },
+        range_coverage: {
+            name: "範囲が表す区間 (既定値: 0.95)",
+            description: "正規分布モデルで、マトリクスの最小–最大の範囲が分布のどれだけの割合を含むとみなすか。",
+        },
         param_multi: {
             title: "複数選択形質の扱い",
         },
@@ -185,6 +256,8 @@ export const STR = {
         conflictPenalty: "矛盾ペナルティ",
         gammaNAPenalty: "NAペナルティ (γ)",
         kappa: "平滑化 (κ)",
+        lambda: "尤度の調整指数 (λ)",
+        errorPrior: "誤り率の事前分布の強さ (回答数)",
         tolerance: "許容範囲 (連続値)",
         observationHistory: "操作履歴 (選択順)",
         noObservations: "観察は行われませんでした。",
@@ -195,6 +268,19 @@ export const STR = {
         scoreHeader: "スコア (確率)",
         conflictsHeader: "矛盾数",
         matchSupportHeader: "一致/適用",
+        confidenceTitle: "同定の信頼度",
+        confidenceDisclaimer: "注意: この信頼度評価は、現在使用しているマトリクスに含まれる分類群のみを対象とした相対的なものです。候補にない種である可能性も常に考慮してください。",
+        unknownTaxon: "マトリクスにない分類群である確率",
+        confidentAtRank: "確率{threshold}以上で確定できる最下位の階級",
+        noConfidentRank: "どの階級でも確率{threshold}に達するグループはありません",
+        confidenceLevel: "信頼度",
+        confidenceHigh: "高い (1位の候補は2位より {ratio}倍確からしい)",
+        confidenceVeryHigh: "非常に高い (2位以下の候補の確率は0です)",
+        confidenceAbsolute: "決定的 (候補は1つに絞り込まれました)",
+        confidenceContested: "要検討 (上位候補が競合しており、単一のタクソンを断定するには情報が不十分です。追加の形質観察を推奨します)",
+        confidenceNoInformative: "要検討 (上位候補が競合していますが、残りの形質ではこれ以上絞り込めません)",
+        confidenceNA: "N/A (候補なし)",
+        credibleSet: "{level}信用集合に含まれる分類群数",
     },
     // --- ▲▲▲ ここまで ▲▲▲ ---
   },
@@ -243,12 +329,18 @@ export const STR = {
         select_all: "Select All",
         deselect_all: "Deselect All",
         show_match_support: "Show Match/Support",
+        verdict_stop: "Identified",
+        verdict_continue: "Keep observing",
+        verdict_no_informative: "No informative traits left",
+        verdict_tooltip: "{level} credible set: {size} taxa / top÷second: {ratio} / best IG: {ig} bits",
+        eliminated: "Eliminated by conflicts ({n})",
     },
     comparisonPanel: {
         title: "Taxa Comparison",
         select_prompt: "Select two or more taxa from the candidate list to compare.",
         hide_same_traits: "Hide traits with no differences",
         trait: "Trait",
+        state_variable: "variable / ±",
     },
     traitsPanel: {
         sort_recommend: "Recommended",
@@ -263,18 +355,58 @@ export const STR = {
         multi_select_tooltip: "Multiple selections are possible",
         tooltip_na: "Use when a trait is 'Unobservable' (e.g., due to specimen damage). This trait will be excluded from the calculation.",
         tooltip_clear: "Clears the selection for this trait.",
+        measurement_error: "± error",
+        group_na: "Mark group unobservable",
+        hedge_sure: "Sure",
+        hedge_probably: "Probably",
+        tooltip_hedge: "A \"probably\" answer counts as weaker evidence (70%) than a sure one.",
     },
     justificationPanel: {
         title_prefix: "Justification for:",
         matches: "Matches",
         conflicts: "Conflicts",
         unobserved: "Unobserved",
+        unobservable: "Unobservable",
+        inapplicable: "Inapplicable (dependency)",
         header_trait: "Trait",
         header_your_choice: "Your Choice",
         header_taxon_data: "Taxon Data",
         none: "None",
         no_data: "No data available.",
     },
+    verifiedCases: {
+        title: "Confirmed Identifications",
+        record_prompt: "Record the current observations as a confirmed case of:",
+        note: "Note",
+        record: "Record",
+        recorded: "Recorded",
+        cases_title: "Recorded Cases",
+        header_taxon: "Taxon (#TaxonID)",
+        header_recorded: "Recorded",
+        header_trait: "Trait",
+        delete: "Delete",
+        suggestions_title: "Suggested Error Rates",
+        suggestions_description: "Estimated from the recorded cases (current → suggested; below, wrong answers/answers). Use them when revising the #AlphaFP / #BetaFN / #Risk columns of the matrix.",
+        none: "None",
+        close: "Close",
+        open_button: "Confirmed Cases",
+        record_tooltip: "Record as confirmed for this taxon",
+    },
+    ensemble: {
+        title: "Algorithm Comparison",
+        description: "Every registered algorithm evaluates the same observations and their rankings are shown side by side. The consensus rank orders taxa by mean rank. Top-ranked taxa whose ranks differ widely between algorithms are flagged.",
+        open_button: "Compare Algorithms",
+        header_consensus: "Cons.",
+        header_name: "Name",
+        header_mean_rank: "Mean Rank",
+        header_spread: "Spread",
+        tooltip_consensus: "Consensus rank, by mean rank",
+        tooltip_spread: "Worst rank minus best rank",
+        disagreement_count: "Taxa ranked inconsistently: {n}",
+        only_disagreements: "Show disagreements only",
+        none: "None",
+        close: "Close",
+    },
     candidatesTab: {
         algorithm: "Algorithm",
         effect_label: "Effect on Results",
@@ -292,6 +424,11 @@ export const STR = {
                 { setting: "Lenient (Low Value)", pro: "Robust against errors.", con: "Candidate list is less refined." }
             ]
         },
+        param_tolerant: {
+            name: "Tolerate a Few Conflicts (Tolerant)",
+            max: "Conflicts Allowed",
+            description: "When enabled, only candidates with more conflicts than this are eliminated. Eliminated candidates are listed below the candidate list.",
+        },
         param_na: {
             title: "Missing Data (NA) Handling",
             name: "γ (NA Penalty) (Default: 0.8)",
@@ -323,6 +460,7 @@ export const STR = {
           description: "(Default: 0.07) Informs the model of the probability of observing 'No' when the true state is 'Yes'.",
         },
         reset_defaults: "Reset to Defaults",
+        advanced: "Advanced…",
       },
     traitsTab: {
         param_recommend: {
@@ -333,15 +471,21 @@ export const STR = {
             options: {
                 stable: "Stable Progress (for Beginners)",
                 breakthrough: "Breakthrough (for Experts)",
+                plan: "Look-ahead Plan (Cost-aware)",
             },
             table_header_strategy: "Strategy",
             table_header_merit: "Merit",
             table_header_demerit: "Demerit",
             tradeoffs: [
               { setting: "Breakthrough", pro: "Allows experts to quickly test hypotheses with decisive traits.", con: "May recommend traits that are uninformative in most cases." },
-              { setting: "Stable Progress", pro: "Easy for beginners to follow a steady path to identification.", con: "May overlook 'killer traits' that could provide a shortcut." }
+              { setting: "Stable Progress", pro: "Easy for beginners to follow a steady path to identification.", con: "May overlook 'killer traits' that could provide a shortcut." },
+              { setting: "Look-ahead Plan", pro: "Searches a few observations ahead and picks the trait that minimises the total difficulty needed to finish.", con: "Slower to compute; recommendations update with a delay on large matrices." }
             ]
         },
+        plan_depth: {
+            name: "Look-ahead Depth (Default: 2)",
+            description: "How many observations ahead the plan is searched. Deeper is more accurate but much slower.",
+        },
         pragmatic_score: {
             name: "Enable Pragmatic Score (Default: Enabled)",
             description: "If enabled, recommends the next trait to observe based not just on information theory, but also on 'ease of observation' (cost) and 'risk of misinterpretation'.",
@@ -358,6 +502,19 @@ export const STR = {
             name: "Tolerance (Default: 10%)",
             description: "Sets how much a user's input for a continuous value (like length) can deviate from the range in the matrix and still be considered a 'match'.",
         },
+        continuous_algo: {
+            name: "Likelihood Model (Default: Range)",
+            description: "'Range' counts a value inside the range as a match and outside as a mismatch. The normal models read the min-max range as an interval of a distribution, so values far from its centre become gradually less likely.",
+            options: {
+                range: "Range",
+                gaussian: "Gaussian",
+                truncated_normal: "Truncated Normal",
+            },
+        },
+        range_coverage: {
+            name: "Interval Covered by the Range (Default: 0.95)",
+            description: "Under the normal models, the share of the distribution the matrix's min-max range is taken to cover.",
+        },
         param_multi: {
             title: "Multi-Select Trait Handling",
         },
@@ -382,6 +539,8 @@ export const STR = {
         conflictPenalty: "Conflict Penalty",
         gammaNAPenalty: "NA Penalty (γ)",
         kappa: "Smoothing (κ)",
+        lambda: "Likelihood Tempering (λ)",
+        errorPrior: "Error-Rate Prior Strength (answers)",
         tolerance: "Tolerance (Continuous)",
         observationHistory: "Observation History (in order of selection)",
         noObservations: "No observations were made.",
@@ -392,6 +551,19 @@ export const STR = {
         scoreHeader: "Score (Prob.)",
         conflictsHeader: "Conflicts",
         matchSupportHeader: "Match/Sup.",
+        confidenceTitle: "Identification Confidence",
+        confidenceDisclaimer: "Note: This confidence assessment is relative and only considers taxa included in the current matrix. Always consider the possibility that the specimen may belong to a taxon not present in this key.",
+        unknownTaxon: "Probability of a taxon not in this key",
+        confidentAtRank: "Lowest rank identified with at least {threshold} probability",
+        noConfidentRank: "No group reaches {threshold} probability at any rank",
+        confidenceLevel: "Confidence",
+        confidenceHigh: "High (Top candidate is {ratio}x more likely than the second)",
+        confidenceVeryHigh: "Very High (Second candidate has a probability of zero)",
+        confidenceAbsolute: "Conclusive (Only one candidate remains)",
+        confidenceContested: "Contested (Top candidates are too close to make a definitive identification. Additional character observation is recommended)",
+        confidenceNoInformative: "Contested (Top candidates are close, and none of the remaining characters is expected to separate them)",
+        confidenceNA: "N/A (No candidates)",
+        credibleSet: "Taxa in the {level} credible set",
     },
     // --- ▲▲▲ ここまで ▲▲▲ ---
   },
@@ -2,15 +2,19 @@
 import { ApplyFiltersAlgoOpt } from "../../wailsjs/go/main/App";
 import { main } from "../../wailsjs/go/models";
 import { AlgoOptions } from "../hooks/useAlgoOpts";
-import { MultiChoice } from "../api";
+import { MultiChoice, ContinuousObservation } from "../api";
 
 export type ApplyResult = main.ApplyResultEx;
 
+// Which taxa a conflict eliminates; "tolerant" allows opts.maxConflicts of them.
+export type EvalMode = "strict" | "lenient" | "tolerant";
+
 export async function applyFilters(
   selected: Record<string, number>,
   selectedMulti: Record<string, MultiChoice>,
-  mode: "strict" | "lenient",
-  algorithm: "bayes" | "heuristic",
+  selectedContinuous: Record<string, ContinuousObservation>,
+  mode: EvalMode,
+  algorithm: string,
   opts: AlgoOptions
 ): Promise<ApplyResult> {
 
@@ -18,6 +22,7 @@ export async function applyFilters(
   const request: main.ApplyRequest = new main.ApplyRequest({
     selected: selected,
     selectedMulti: selectedMulti,
+    selectedContinuous: { ...selectedContinuous },
     mode: mode,
     algo: algorithm,
     opts: {
@@ -30,4 +35,4 @@ export async function applyFilters(
   const res = await ApplyFiltersAlgoOpt(request);
 
   return res as ApplyResult;
-}
\ No newline at end of file
+}
\ No newline at end of file
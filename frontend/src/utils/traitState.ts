@@ -0,0 +1,28 @@
+// frontend/src/utils/traitState.ts
+import { Trait, Taxon } from "../api";
+
+// traitState returns the text shown for a taxon's cell of a user-selectable trait.
+// variableLabel is shown for a polymorphic binary cell (2), which is not unknown.
+export const traitState = (taxon: Taxon, trait: Trait, allTraits: Trait[], variableLabel: string): string => {
+    if (trait.type === 'binary') {
+        const state = taxon.traits?.[trait.id];
+        switch (state) {
+            case 1: return "Yes";
+            case -1: return "No";
+            case 2: return variableLabel;
+            default: return "NA";
+        }
+    }
+
+    if (trait.type === 'nominal_parent') {
+        const childTraits = allTraits.filter(t => t.parent === trait.traitId);
+
+        for (const child of childTraits) {
+            if (taxon.traits?.[child.id] === 1) {
+                return child.state || child.name_en;
+            }
+        }
+    }
+
+    return "NA";
+};
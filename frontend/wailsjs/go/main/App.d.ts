@@ -4,10 +4,16 @@ import {main} from '../models';
 import {engine} from '../models';
 import {context} from '../models';
 
+export function AddVerifiedCase(arg1:string,arg2:main.ApplyRequest,arg3:string):Promise<void>;
+
+export function ApplyEnsemble(arg1:main.ApplyRequest):Promise<engine.EnsembleResult>;
+
 export function ApplyFiltersAlgoOpt(arg1:main.ApplyRequest):Promise<main.ApplyResultEx>;
 
 export function CreateNewMatrix(arg1:string):Promise<void>;
 
+export function DeleteVerifiedCase(arg1:number):Promise<void>;
+
 export function EnsureMyKeysAndSamples():Promise<void>;
 
 export function GenerateUUID():Promise<string>;
@@ -16,20 +22,28 @@ export function GetCurrentKeyName():Promise<string>;
 
 export function GetHelpImage(arg1:string):Promise<string>;
 
-export function GetJustificationForTaxon(arg1:string,arg2:Record<string, number>,arg3:Record<string, Array<string>>):Promise<main.Justification>;
+export function GetJustificationForRequest(arg1:string,arg2:main.ApplyRequest):Promise<main.Justification>;
+
+export function GetJustificationForTaxon(arg1:string,arg2:Record<string, number>,arg3:Record<string, Array<string>>,arg4:Record<string, engine.ContinuousObservation>):Promise<main.Justification>;
 
 export function GetKeysDirectory():Promise<string>;
 
 export function GetMatrix():Promise<engine.Matrix>;
 
+export function GetMatrixIssues():Promise<Array<engine.LoadIssue>>;
+
 export function GetTaxonDetails(arg1:string):Promise<engine.Taxon>;
 
+export function ListAlgorithms():Promise<Array<engine.AlgorithmInfo>>;
+
 export function ListHelperImages():Promise<Array<string>>;
 
 export function ListMatrixFiles():Promise<Array<string>>;
 
 export function ListMyKeys():Promise<Array<main.KeyInfo>>;
 
+export function ListVerifiedCases():Promise<Array<main.VerifiedCase>>;
+
 export function LoadMatrix(arg1:string):Promise<main.MatrixData>;
 
 export function PickKey(arg1:string):Promise<void>;
@@ -41,3 +55,5 @@ export function SaveReport(arg1:string,arg2:string,arg3:string):Promise<string>;
 export function SelectKeysDirectory():Promise<string>;
 
 export function Startup(arg1:context.Context):Promise<void>;
+
+export function SuggestErrorRateUpdates(arg1:number,arg2:number,arg3:number):Promise<Array<engine.TraitCalibration>>;
@@ -2,6 +2,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddVerifiedCase(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddVerifiedCase'](arg1, arg2, arg3);
+}
+
+export function ApplyEnsemble(arg1) {
+  return window['go']['main']['App']['ApplyEnsemble'](arg1);
+}
+
 export function ApplyFiltersAlgoOpt(arg1) {
   return window['go']['main']['App']['ApplyFiltersAlgoOpt'](arg1);
 }
@@ -10,6 +18,10 @@ export function CreateNewMatrix(arg1) {
   return window['go']['main']['App']['CreateNewMatrix'](arg1);
 }
 
+export function DeleteVerifiedCase(arg1) {
+  return window['go']['main']['App']['DeleteVerifiedCase'](arg1);
+}
+
 export function EnsureMyKeysAndSamples() {
   return window['go']['main']['App']['EnsureMyKeysAndSamples']();
 }
@@ -26,8 +38,12 @@ export function GetHelpImage(arg1) {
   return window['go']['main']['App']['GetHelpImage'](arg1);
 }
 
-export function GetJustificationForTaxon(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetJustificationForTaxon'](arg1, arg2, arg3);
+export function GetJustificationForRequest(arg1, arg2) {
+  return window['go']['main']['App']['GetJustificationForRequest'](arg1, arg2);
+}
+
+export function GetJustificationForTaxon(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetJustificationForTaxon'](arg1, arg2, arg3, arg4);
 }
 
 export function GetKeysDirectory() {
@@ -38,10 +54,18 @@ export function GetMatrix() {
   return window['go']['main']['App']['GetMatrix']();
 }
 
+export function GetMatrixIssues() {
+  return window['go']['main']['App']['GetMatrixIssues']();
+}
+
 export function GetTaxonDetails(arg1) {
   return window['go']['main']['App']['GetTaxonDetails'](arg1);
 }
 
+export function ListAlgorithms() {
+  return window['go']['main']['App']['ListAlgorithms']();
+}
+
 export function ListHelperImages() {
   return window['go']['main']['App']['ListHelperImages']();
 }
@@ -54,6 +78,10 @@ export function ListMyKeys() {
   return window['go']['main']['App']['ListMyKeys']();
 }
 
+export function ListVerifiedCases() {
+  return window['go']['main']['App']['ListVerifiedCases']();
+}
+
 export function LoadMatrix(arg1) {
   return window['go']['main']['App']['LoadMatrix'](arg1);
 }
@@ -77,3 +105,7 @@ export function SelectKeysDirectory() {
 export function Startup(arg1) {
   return window['go']['main']['App']['Startup'](arg1);
 }
+
+export function SuggestErrorRateUpdates(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SuggestErrorRateUpdates'](arg1, arg2, arg3);
+}
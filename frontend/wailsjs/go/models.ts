@@ -1,89 +1,652 @@
 export namespace engine {
 	
+	export class AlgoParam {
+	    key: string;
+	    type: string;
+	    description: string;
+	    default?: any;
+	    min?: number;
+	    max?: number;
+	    choices?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AlgoParam(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.type = source["type"];
+	        this.description = source["description"];
+	        this.default = source["default"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.choices = source["choices"];
+	    }
+	}
+	export class AlgorithmInfo {
+	    name: string;
+	    label: string;
+	    description: string;
+	    probabilistic: boolean;
+	    params: AlgoParam[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AlgorithmInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.description = source["description"];
+	        this.probabilistic = source["probabilistic"];
+	        this.params = this.convertValues(source["params"], AlgoParam);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ContinuousObservation {
+	    value: number;
+	    error?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContinuousObservation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.value = source["value"];
+	        this.error = source["error"];
+	    }
+	}
+	export class Observations {
+	    selected: Record<string, number>;
+	    selectedMulti: Record<string, Array<string>>;
+	    selectedNA: Record<string, boolean>;
+	    selectedNAGroups: string[];
+	    selectedNominal: Record<string, string>;
+	    selectedContinuous: Record<string, ContinuousObservation>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Observations(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.selected = source["selected"];
+	        this.selectedMulti = source["selectedMulti"];
+	        this.selectedNA = source["selectedNA"];
+	        this.selectedNAGroups = source["selectedNAGroups"];
+	        this.selectedNominal = source["selectedNominal"];
+	        this.selectedContinuous = this.convertValues(source["selectedContinuous"], ContinuousObservation, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfirmedID {
+	    taxonId: string;
+	    observations: Observations;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfirmedID(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taxonId = source["taxonId"];
+	        this.observations = this.convertValues(source["observations"], Observations);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class ContinuousValue {
 	    min: number;
 	    max: number;
+	    mean?: number;
+	    sd?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContinuousValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.mean = source["mean"];
+	        this.sd = source["sd"];
+	    }
+	}
+	export class CredibleSet {
+	    level: number;
+	    taxonIds: string[];
+	    mass: number;
+	    reached: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CredibleSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.taxonIds = source["taxonIds"];
+	        this.mass = source["mass"];
+	        this.reached = source["reached"];
+	    }
+	}
+	export class Dependency {
+	    parentTraitId: string;
+	    requiredState: string;
+	    expr?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Dependency(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.parentTraitId = source["parentTraitId"];
+	        this.requiredState = source["requiredState"];
+	        this.expr = source["expr"];
+	    }
+	}
+	export class Taxon {
+	    id: string;
+	    name: string;
+	    scientificName: string;
+	    rank?: string;
+	    taxonAuthor?: string;
+	    vernacularName_en?: string;
+	    vernacularName_ja?: string;
+	    description_en?: string;
+	    description_ja?: string;
+	    images?: string[];
+	    references?: string;
+	    prior?: number;
+	    traits: Record<string, number>;
+	    continuousTraits: Record<string, ContinuousValue>;
+	    categoricalTraits: Record<string, Array<string>>;
+	    inapplicable?: Record<string, boolean>;
+	    order?: string;
+	    superfamily?: string;
+	    family?: string;
+	    subfamily?: string;
+	    tribe?: string;
+	    subtribe?: string;
+	    genus?: string;
+	    subgenus?: string;
+	    species?: string;
+	    subspecies?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Taxon(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.scientificName = source["scientificName"];
+	        this.rank = source["rank"];
+	        this.taxonAuthor = source["taxonAuthor"];
+	        this.vernacularName_en = source["vernacularName_en"];
+	        this.vernacularName_ja = source["vernacularName_ja"];
+	        this.description_en = source["description_en"];
+	        this.description_ja = source["description_ja"];
+	        this.images = source["images"];
+	        this.references = source["references"];
+	        this.prior = source["prior"];
+	        this.traits = source["traits"];
+	        this.continuousTraits = this.convertValues(source["continuousTraits"], ContinuousValue, true);
+	        this.categoricalTraits = source["categoricalTraits"];
+	        this.inapplicable = source["inapplicable"];
+	        this.order = source["order"];
+	        this.superfamily = source["superfamily"];
+	        this.family = source["family"];
+	        this.subfamily = source["subfamily"];
+	        this.tribe = source["tribe"];
+	        this.subtribe = source["subtribe"];
+	        this.genus = source["genus"];
+	        this.subgenus = source["subgenus"];
+	        this.species = source["species"];
+	        this.subspecies = source["subspecies"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EnsembleEntry {
+	    taxon: Taxon;
+	    ranks: Record<string, number>;
+	    scores: Record<string, number>;
+	    meanRank: number;
+	    consensusRank: number;
+	    rankSpread: number;
+	    disagreement: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnsembleEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taxon = this.convertValues(source["taxon"], Taxon);
+	        this.ranks = source["ranks"];
+	        this.scores = source["scores"];
+	        this.meanRank = source["meanRank"];
+	        this.consensusRank = source["consensusRank"];
+	        this.rankSpread = source["rankSpread"];
+	        this.disagreement = source["disagreement"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StopVerdict {
+	    stop: boolean;
+	    confidence: string;
+	    reason: string;
+	    setSize: number;
+	    ratio: number;
+	    bestIG: number;
+	    hasIG: boolean;
+	    level: number;
+	    stopRatio: number;
+	    minInfoGain: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StopVerdict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stop = source["stop"];
+	        this.confidence = source["confidence"];
+	        this.reason = source["reason"];
+	        this.setSize = source["setSize"];
+	        this.ratio = source["ratio"];
+	        this.bestIG = source["bestIG"];
+	        this.hasIG = source["hasIG"];
+	        this.level = source["level"];
+	        this.stopRatio = source["stopRatio"];
+	        this.minInfoGain = source["minInfoGain"];
+	    }
+	}
+	export class RankConfidence {
+	    rank: string;
+	    name: string;
+	    post: number;
+	    threshold: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RankConfidence(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rank = source["rank"];
+	        this.name = source["name"];
+	        this.post = source["post"];
+	        this.threshold = source["threshold"];
+	    }
+	}
+	export class RankNode {
+	    rank: string;
+	    name: string;
+	    taxonId?: string;
+	    post: number;
+	    count: number;
+	    children?: RankNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RankNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rank = source["rank"];
+	        this.name = source["name"];
+	        this.taxonId = source["taxonId"];
+	        this.post = source["post"];
+	        this.count = source["count"];
+	        this.children = this.convertValues(source["children"], RankNode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Hierarchy {
+	    roots: RankNode[];
+	    confident?: RankConfidence;
+	
+	    static createFrom(source: any = {}) {
+	        return new Hierarchy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.roots = this.convertValues(source["roots"], RankNode);
+	        this.confident = this.convertValues(source["confident"], RankConfidence);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StateProb {
+	    state: string;
+	    p: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StateProb(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.state = source["state"];
+	        this.p = source["p"];
+	    }
+	}
+	export class TraitSuggestion {
+	    traitId: string;
+	    name: string;
+	    group: string;
+	    ig: number;
+	    max_ig: number;
+	    ecr: number;
+	    gini: number;
+	    entropy: number;
+	    pStates: StateProb[];
+	    difficulty?: number;
+	    risk?: number;
+	    score: number;
+	    expectedCost?: number;
+	    expectedSteps?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TraitSuggestion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.traitId = source["traitId"];
+	        this.name = source["name"];
+	        this.group = source["group"];
+	        this.ig = source["ig"];
+	        this.max_ig = source["max_ig"];
+	        this.ecr = source["ecr"];
+	        this.gini = source["gini"];
+	        this.entropy = source["entropy"];
+	        this.pStates = this.convertValues(source["pStates"], StateProb);
+	        this.difficulty = source["difficulty"];
+	        this.risk = source["risk"];
+	        this.score = source["score"];
+	        this.expectedCost = source["expectedCost"];
+	        this.expectedSteps = source["expectedSteps"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaxonScore {
+	    index: number;
+	    taxon: Taxon;
+	    post: number;
+	    delta: number;
+	    used: number;
+	    conflicts: number;
+	    match: number;
+	    support: number;
+	    distance?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaxonScore(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.taxon = this.convertValues(source["taxon"], Taxon);
+	        this.post = source["post"];
+	        this.delta = source["delta"];
+	        this.used = source["used"];
+	        this.conflicts = source["conflicts"];
+	        this.match = source["match"];
+	        this.support = source["support"];
+	        this.distance = source["distance"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EvalResult {
+	    scores: TaxonScore[];
+	    suggestions: TraitSuggestion[];
+	    inactive?: string[];
+	    dropped?: TaxonScore[];
+	    unknown?: number;
+	    hierarchy?: Hierarchy;
+	    credible?: CredibleSet;
+	    verdict?: StopVerdict;
 	
 	    static createFrom(source: any = {}) {
-	        return new ContinuousValue(source);
+	        return new EvalResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.min = source["min"];
-	        this.max = source["max"];
+	        this.scores = this.convertValues(source["scores"], TaxonScore);
+	        this.suggestions = this.convertValues(source["suggestions"], TraitSuggestion);
+	        this.inactive = source["inactive"];
+	        this.dropped = this.convertValues(source["dropped"], TaxonScore);
+	        this.unknown = source["unknown"];
+	        this.hierarchy = this.convertValues(source["hierarchy"], Hierarchy);
+	        this.credible = this.convertValues(source["credible"], CredibleSet);
+	        this.verdict = this.convertValues(source["verdict"], StopVerdict);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class Dependency {
-	    parentTraitId: string;
-	    requiredState: string;
+	export class EnsembleRun {
+	    algo: string;
+	    result?: EvalResult;
 	
 	    static createFrom(source: any = {}) {
-	        return new Dependency(source);
+	        return new EnsembleRun(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.parentTraitId = source["parentTraitId"];
-	        this.requiredState = source["requiredState"];
+	        this.algo = source["algo"];
+	        this.result = this.convertValues(source["result"], EvalResult);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class Taxon {
-	    id: string;
-	    name: string;
-	    scientificName: string;
-	    rank?: string;
-	    taxonAuthor?: string;
-	    vernacularName_en?: string;
-	    vernacularName_ja?: string;
-	    description_en?: string;
-	    description_ja?: string;
-	    images?: string[];
-	    references?: string;
-	    traits: Record<string, number>;
-	    continuousTraits: Record<string, ContinuousValue>;
-	    categoricalTraits: Record<string, Array<string>>;
-	    order?: string;
-	    superfamily?: string;
-	    family?: string;
-	    subfamily?: string;
-	    tribe?: string;
-	    subtribe?: string;
-	    genus?: string;
-	    subgenus?: string;
-	    species?: string;
-	    subspecies?: string;
+	export class EnsembleResult {
+	    runs: EnsembleRun[];
+	    consensus: EnsembleEntry[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Taxon(source);
+	        return new EnsembleResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.scientificName = source["scientificName"];
-	        this.rank = source["rank"];
-	        this.taxonAuthor = source["taxonAuthor"];
-	        this.vernacularName_en = source["vernacularName_en"];
-	        this.vernacularName_ja = source["vernacularName_ja"];
-	        this.description_en = source["description_en"];
-	        this.description_ja = source["description_ja"];
-	        this.images = source["images"];
-	        this.references = source["references"];
-	        this.traits = source["traits"];
-	        this.continuousTraits = this.convertValues(source["continuousTraits"], ContinuousValue, true);
-	        this.categoricalTraits = source["categoricalTraits"];
-	        this.order = source["order"];
-	        this.superfamily = source["superfamily"];
-	        this.family = source["family"];
-	        this.subfamily = source["subfamily"];
-	        this.tribe = source["tribe"];
-	        this.subtribe = source["subtribe"];
-	        this.genus = source["genus"];
-	        this.subgenus = source["subgenus"];
-	        this.species = source["species"];
-	        this.subspecies = source["subspecies"];
+	        this.runs = this.convertValues(source["runs"], EnsembleRun);
+	        this.consensus = this.convertValues(source["consensus"], EnsembleEntry);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -104,6 +667,31 @@ export namespace engine {
 		    return a;
 		}
 	}
+	
+	
+	
+	export class LoadIssue {
+	    sheet: string;
+	    row: number;
+	    traitId?: string;
+	    column?: string;
+	    value?: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoadIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sheet = source["sheet"];
+	        this.row = source["row"];
+	        this.traitId = source["traitId"];
+	        this.column = source["column"];
+	        this.value = source["value"];
+	        this.message = source["message"];
+	    }
+	}
 	export class Trait {
 	    id: string;
 	    traitId?: string;
@@ -118,6 +706,8 @@ export namespace engine {
 	    state?: string;
 	    difficulty?: number;
 	    risk?: number;
+	    alphaFP?: number;
+	    betaFN?: number;
 	    helpText_en?: string;
 	    helpText_jp?: string;
 	    helpImages?: string[];
@@ -125,6 +715,7 @@ export namespace engine {
 	    maxValue?: number;
 	    isInteger?: boolean;
 	    states?: string[];
+	    ordinal?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Trait(source);
@@ -145,6 +736,8 @@ export namespace engine {
 	        this.state = source["state"];
 	        this.difficulty = source["difficulty"];
 	        this.risk = source["risk"];
+	        this.alphaFP = source["alphaFP"];
+	        this.betaFN = source["betaFN"];
 	        this.helpText_en = source["helpText_en"];
 	        this.helpText_jp = source["helpText_jp"];
 	        this.helpImages = source["helpImages"];
@@ -152,6 +745,7 @@ export namespace engine {
 	        this.maxValue = source["maxValue"];
 	        this.isInteger = source["isInteger"];
 	        this.states = source["states"];
+	        this.ordinal = source["ordinal"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -213,6 +807,7 @@ export namespace engine {
 	    info: MatrixInfo;
 	    traits: Trait[];
 	    taxa: Taxon[];
+	    issues?: LoadIssue[];
 	
 	    static createFrom(source: any = {}) {
 	        return new Matrix(source);
@@ -224,6 +819,7 @@ export namespace engine {
 	        this.info = this.convertValues(source["info"], MatrixInfo);
 	        this.traits = this.convertValues(source["traits"], Trait);
 	        this.taxa = this.convertValues(source["taxa"], Taxon);
+	        this.issues = this.convertValues(source["issues"], LoadIssue);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -245,98 +841,64 @@ export namespace engine {
 		}
 	}
 	
-	export class StateProb {
-	    state: string;
-	    p: number;
 	
-	    static createFrom(source: any = {}) {
-	        return new StateProb(source);
-	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.state = source["state"];
-	        this.p = source["p"];
-	    }
-	}
 	
-	export class TaxonScore {
-	    index: number;
-	    taxon: Taxon;
-	    post: number;
-	    delta: number;
-	    used: number;
-	    conflicts: number;
-	    match: number;
-	    support: number;
+	
+	
+	
+	
+	
+	export class TraitErrorCounts {
+	    falsePositives: number;
+	    negatives: number;
+	    falseNegatives: number;
+	    positives: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new TaxonScore(source);
+	        return new TraitErrorCounts(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.index = source["index"];
-	        this.taxon = this.convertValues(source["taxon"], Taxon);
-	        this.post = source["post"];
-	        this.delta = source["delta"];
-	        this.used = source["used"];
-	        this.conflicts = source["conflicts"];
-	        this.match = source["match"];
-	        this.support = source["support"];
+	        this.falsePositives = source["falsePositives"];
+	        this.negatives = source["negatives"];
+	        this.falseNegatives = source["falseNegatives"];
+	        this.positives = source["positives"];
 	    }
-	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
 	}
-	
-	export class TraitSuggestion {
-	    traitId: string;
-	    name: string;
-	    group: string;
-	    ig: number;
-	    max_ig: number;
-	    ecr: number;
-	    gini: number;
-	    entropy: number;
-	    pStates: StateProb[];
-	    difficulty?: number;
-	    risk?: number;
-	    score: number;
+	export class TraitCalibration {
+	    id: string;
+	    traitId?: string;
+	    name_en: string;
+	    name_jp: string;
+	    counts: TraitErrorCounts;
+	    alphaFP: number;
+	    suggestedAlphaFP?: number;
+	    betaFN: number;
+	    suggestedBetaFN?: number;
+	    risk: number;
+	    suggestedRisk: number;
+	    riskLabel: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new TraitSuggestion(source);
+	        return new TraitCalibration(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
 	        this.traitId = source["traitId"];
-	        this.name = source["name"];
-	        this.group = source["group"];
-	        this.ig = source["ig"];
-	        this.max_ig = source["max_ig"];
-	        this.ecr = source["ecr"];
-	        this.gini = source["gini"];
-	        this.entropy = source["entropy"];
-	        this.pStates = this.convertValues(source["pStates"], StateProb);
-	        this.difficulty = source["difficulty"];
+	        this.name_en = source["name_en"];
+	        this.name_jp = source["name_jp"];
+	        this.counts = this.convertValues(source["counts"], TraitErrorCounts);
+	        this.alphaFP = source["alphaFP"];
+	        this.suggestedAlphaFP = source["suggestedAlphaFP"];
+	        this.betaFN = source["betaFN"];
+	        this.suggestedBetaFN = source["suggestedBetaFN"];
 	        this.risk = source["risk"];
-	        this.score = source["score"];
+	        this.suggestedRisk = source["suggestedRisk"];
+	        this.riskLabel = source["riskLabel"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -357,6 +919,7 @@ export namespace engine {
 		    return a;
 		}
 	}
+	
 
 }
 
@@ -371,16 +934,28 @@ export namespace main {
 	    toleranceFactor: number;
 	    categoricalAlgo: string;
 	    jaccardThreshold: number;
+	    continuousAlgo?: string;
+	    rangeCoverage?: number;
+	    maxConflicts?: number;
+	    disagreementRanks?: number;
+	    unknownPrior?: number;
+	    rankThreshold?: number;
+	    credibleLevel?: number;
+	    stopRatio?: number;
+	    minInfoGain?: number;
+	    planDepth?: number;
 	    wantInfoGain: boolean;
 	    usePragmaticScore: boolean;
 	    recommendationStrategy: string;
+	    applyDependencies: boolean;
 	    lambda: number;
-	    a0: number;
-	    b0: number;
+	    priorStrength: number;
 	    alphaFP?: Record<string, number>;
 	    betaFN?: Record<string, number>;
 	    confidence?: Record<string, number>;
 	    priors?: Record<string, number>;
+	    confirmed?: engine.ConfirmedID[];
+	    useVerifiedCases?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ApplyOptions(source);
@@ -396,22 +971,55 @@ export namespace main {
 	        this.toleranceFactor = source["toleranceFactor"];
 	        this.categoricalAlgo = source["categoricalAlgo"];
 	        this.jaccardThreshold = source["jaccardThreshold"];
+	        this.continuousAlgo = source["continuousAlgo"];
+	        this.rangeCoverage = source["rangeCoverage"];
+	        this.maxConflicts = source["maxConflicts"];
+	        this.disagreementRanks = source["disagreementRanks"];
+	        this.unknownPrior = source["unknownPrior"];
+	        this.rankThreshold = source["rankThreshold"];
+	        this.credibleLevel = source["credibleLevel"];
+	        this.stopRatio = source["stopRatio"];
+	        this.minInfoGain = source["minInfoGain"];
+	        this.planDepth = source["planDepth"];
 	        this.wantInfoGain = source["wantInfoGain"];
 	        this.usePragmaticScore = source["usePragmaticScore"];
 	        this.recommendationStrategy = source["recommendationStrategy"];
+	        this.applyDependencies = source["applyDependencies"];
 	        this.lambda = source["lambda"];
-	        this.a0 = source["a0"];
-	        this.b0 = source["b0"];
+	        this.priorStrength = source["priorStrength"];
 	        this.alphaFP = source["alphaFP"];
 	        this.betaFN = source["betaFN"];
 	        this.confidence = source["confidence"];
 	        this.priors = source["priors"];
+	        this.confirmed = this.convertValues(source["confirmed"], engine.ConfirmedID);
+	        this.useVerifiedCases = source["useVerifiedCases"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class ApplyRequest {
 	    selected: Record<string, number>;
 	    selectedMulti: Record<string, Array<string>>;
 	    selectedNA: Record<string, boolean>;
+	    selectedNAGroups?: string[];
+	    selectedNominal?: Record<string, string>;
+	    selectedContinuous?: Record<string, engine.ContinuousObservation>;
 	    mode: string;
 	    algo: string;
 	    opts: ApplyOptions;
@@ -425,6 +1033,9 @@ export namespace main {
 	        this.selected = source["selected"];
 	        this.selectedMulti = source["selectedMulti"];
 	        this.selectedNA = source["selectedNA"];
+	        this.selectedNAGroups = source["selectedNAGroups"];
+	        this.selectedNominal = source["selectedNominal"];
+	        this.selectedContinuous = this.convertValues(source["selectedContinuous"], engine.ContinuousObservation, true);
 	        this.mode = source["mode"];
 	        this.algo = source["algo"];
 	        this.opts = this.convertValues(source["opts"], ApplyOptions);
@@ -451,6 +1062,12 @@ export namespace main {
 	export class ApplyResultEx {
 	    scores: engine.TaxonScore[];
 	    suggestions: engine.TraitSuggestion[];
+	    inactive?: string[];
+	    dropped?: engine.TaxonScore[];
+	    unknown?: number;
+	    hierarchy?: engine.Hierarchy;
+	    credible?: engine.CredibleSet;
+	    verdict?: engine.StopVerdict;
 	
 	    static createFrom(source: any = {}) {
 	        return new ApplyResultEx(source);
@@ -460,6 +1077,12 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.scores = this.convertValues(source["scores"], engine.TaxonScore);
 	        this.suggestions = this.convertValues(source["suggestions"], engine.TraitSuggestion);
+	        this.inactive = source["inactive"];
+	        this.dropped = this.convertValues(source["dropped"], engine.TaxonScore);
+	        this.unknown = source["unknown"];
+	        this.hierarchy = this.convertValues(source["hierarchy"], engine.Hierarchy);
+	        this.credible = this.convertValues(source["credible"], engine.CredibleSet);
+	        this.verdict = this.convertValues(source["verdict"], engine.StopVerdict);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -504,6 +1127,8 @@ export namespace main {
 	    matches: JustificationItem[];
 	    conflicts: JustificationItem[];
 	    unobserved: JustificationItem[];
+	    unobservable: JustificationItem[];
+	    inapplicable: JustificationItem[];
 	    matchCount: number;
 	    conflictCount: number;
 	
@@ -516,6 +1141,8 @@ export namespace main {
 	        this.matches = this.convertValues(source["matches"], JustificationItem);
 	        this.conflicts = this.convertValues(source["conflicts"], JustificationItem);
 	        this.unobserved = this.convertValues(source["unobserved"], JustificationItem);
+	        this.unobservable = this.convertValues(source["unobservable"], JustificationItem);
+	        this.inapplicable = this.convertValues(source["inapplicable"], JustificationItem);
 	        this.matchCount = source["matchCount"];
 	        this.conflictCount = source["conflictCount"];
 	    }
@@ -575,6 +1202,42 @@ export namespace main {
 	        this.traits = source["traits"];
 	    }
 	}
+	export class VerifiedCase {
+	    taxonId: string;
+	    observations: engine.Observations;
+	    recordedAt: string;
+	    note?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new VerifiedCase(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taxonId = source["taxonId"];
+	        this.observations = this.convertValues(source["observations"], engine.Observations);
+	        this.recordedAt = source["recordedAt"];
+	        this.note = source["note"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -22,32 +22,56 @@ type ApplyOptions struct {
 	ToleranceFactor        float64            `json:"toleranceFactor"`
 	CategoricalAlgo        string             `json:"categoricalAlgo"`
 	JaccardThreshold       float64            `json:"jaccardThreshold"`
+	ContinuousAlgo         string             `json:"continuousAlgo,omitempty"`    // "range" (default), "gaussian" or "truncated_normal"
+	RangeCoverage          float64            `json:"rangeCoverage,omitempty"`     // Quantile interval a min-max range stands for (default 0.95)
+	MaxConflicts           int                `json:"maxConflicts,omitempty"`      // Conflicts a taxon may have when Mode is "tolerant"
+	DisagreementRanks      int                `json:"disagreementRanks,omitempty"` // Rank spread that flags a taxon in ensemble mode
+	UnknownPrior           float64            `json:"unknownPrior,omitempty"`      // Prior of a "taxon not in this key" class
+	RankThreshold          float64            `json:"rankThreshold,omitempty"`     // Mass a group needs to be "confident at rank X"
+	CredibleLevel          float64            `json:"credibleLevel,omitempty"`     // Mass the credible set must cover
+	StopRatio              float64            `json:"stopRatio,omitempty"`         // Top-to-second ratio needed to stop
+	MinInfoGain            float64            `json:"minInfoGain,omitempty"`       // Expected IG below which no trait is worth asking
+	PlanDepth              int                `json:"planDepth,omitempty"`         // Look-ahead of the "plan" recommendation strategy
 	WantInfoGain           bool               `json:"wantInfoGain"`
 	UsePragmaticScore      bool               `json:"usePragmaticScore"`
 	RecommendationStrategy string             `json:"recommendationStrategy"`
-	Lambda                 float64            `json:"lambda"`
-	A0                     float64            `json:"a0"`
-	B0                     float64            `json:"b0"`
+	ApplyDependencies      bool               `json:"applyDependencies"` // Treat traits ruled out by #Dependency as inapplicable
+	Lambda                 float64            `json:"lambda"`            // Likelihood tempering exponent
+	PriorStrength          float64            `json:"priorStrength"`     // Weight in answers of the prior on per-trait error rates
 	AlphaFP                map[string]float64 `json:"alphaFP,omitempty"`
 	BetaFN                 map[string]float64 `json:"betaFN,omitempty"`
 	Confidence             map[string]float64 `json:"confidence,omitempty"`
 	Priors                 map[string]float64 `json:"priors,omitempty"`
+
+	// Confirmed identifications for the PriorStrength update of per-trait error rates.
+	Confirmed []engine.ConfirmedID `json:"confirmed,omitempty"`
+	// UseVerifiedCases adds the cases stored for the current matrix to Confirmed.
+	UseVerifiedCases bool `json:"useVerifiedCases,omitempty"`
 }
 
 // ApplyRequest フロントからの全リクエストをまとめる構造体
 type ApplyRequest struct {
-	Selected      map[string]int      `json:"selected"`
-	SelectedMulti map[string][]string `json:"selectedMulti"`
-	SelectedNA    map[string]bool     `json:"selectedNA"` // NEW: For unobservable traits
-	Mode          string              `json:"mode"`
-	Algo          string              `json:"algo"`
-	Opts          ApplyOptions        `json:"opts"`
+	Selected           map[string]int                          `json:"selected"`
+	SelectedMulti      map[string][]string                     `json:"selectedMulti"`
+	SelectedNA         map[string]bool                         `json:"selectedNA"`                   // NEW: For unobservable traits
+	SelectedNAGroups   []string                                `json:"selectedNAGroups,omitempty"`   // Whole trait groups (EN or JP name) marked as unobservable
+	SelectedNominal    map[string]string                       `json:"selectedNominal,omitempty"`    // One state label per nominal/ordinal trait, instead of child IDs
+	SelectedContinuous map[string]engine.ContinuousObservation `json:"selectedContinuous,omitempty"` // Measured value (± error) per continuous trait
+	Mode               string                                  `json:"mode"`
+	Algo               string                                  `json:"algo"`
+	Opts               ApplyOptions                            `json:"opts"`
 }
 
 // ApplyResultEx バックエンド→フロント：スコアと推薦をまとめて返す
 type ApplyResultEx struct {
 	Scores      []engine.TaxonScore      `json:"scores"`
 	Suggestions []engine.TraitSuggestion `json:"suggestions"`
+	Inactive    []string                 `json:"inactive,omitempty"`  // Trait IDs made inapplicable by an observed #Dependency parent
+	Dropped     []engine.TaxonScore      `json:"dropped,omitempty"`   // Taxa eliminated by the strict/tolerant mode
+	Unknown     float64                  `json:"unknown,omitempty"`   // Posterior of "taxon not in this key"
+	Hierarchy   *engine.Hierarchy        `json:"hierarchy,omitempty"` // Probability summed by taxonomic rank
+	Credible    *engine.CredibleSet      `json:"credible,omitempty"`  // Smallest set of taxa covering CredibleLevel
+	Verdict     *engine.StopVerdict      `json:"verdict,omitempty"`   // Whether to stop or keep observing
 }
 
 // JustificationItem 「なぜ？」機能で各形質の状態を示すための構造体
@@ -56,7 +80,7 @@ type JustificationItem struct {
 	TraitGroupName string `json:"traitGroupName"`
 	UserChoice     string `json:"userChoice"`
 	TaxonState     string `json:"taxonState"`
-	Status         string `json:"status"` // "match", "conflict", "unobserved"
+	Status         string `json:"status"` // "match", "near", "conflict", "unobserved", "unobservable", "inapplicable"
 }
 
 // Justification 「なぜ？」機能の全体的な結果
@@ -64,6 +88,8 @@ type Justification struct {
 	Matches       []JustificationItem `json:"matches"`
 	Conflicts     []JustificationItem `json:"conflicts"`
 	Unobserved    []JustificationItem `json:"unobserved"`
+	Unobservable  []JustificationItem `json:"unobservable"` // Traits the user marked as impossible to observe
+	Inapplicable  []JustificationItem `json:"inapplicable"` // Dependent traits ruled out by the observed parent state
 	MatchCount    int                 `json:"matchCount"`
 	ConflictCount int                 `json:"conflictCount"`
 }
@@ -93,3 +119,10 @@ type MatrixData struct {
 	TaxaInfo   [][]string `json:"taxaInfo"`
 	Traits     [][]string `json:"traits"`
 }
+
+// VerifiedCase is a confirmed identification stored for the current matrix.
+type VerifiedCase struct {
+	engine.ConfirmedID
+	RecordedAt string `json:"recordedAt"`
+	Note       string `json:"note,omitempty"`
+}